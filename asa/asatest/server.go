@@ -0,0 +1,814 @@
+// Package asatest provides an in-process fake of the ASA API for tests.
+//
+// The fake keeps its state in memory and implements the endpoints used by
+// the asa package, including ASA's soft deletes: deleted projects and groups
+// are still returned with deleted_at set, and removed project groups with
+// removed_at set. Service users cannot be deleted, only disabled.
+//
+// Users synced from Okta cannot be created through the API; tests add them
+// with AddUser.
+package asatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+// Server is a fake ASA API serving a single team.
+type Server struct {
+	*httptest.Server
+
+	Team      string
+	KeyID     string
+	KeySecret string
+
+	mu       sync.Mutex
+	tokens   map[string]bool
+	nextID   int
+	projects map[string]*asa.Project
+	groups   map[string]*asa.Group
+	// keyed by group name, then user name
+	groupUsers map[string]map[string]bool
+	// keyed by group name, then attribute ID
+	groupAttributes map[string]map[string]*asa.Attribute
+
+	users        map[string]bool
+	serviceUsers map[string]*asa.ServiceUser
+	// keyed by service user name, then key ID
+	serviceUserKeys map[string]map[string]*asa.ServiceUserKey
+	// keyed by user name, then attribute ID
+	userAttributes map[string]map[string]*asa.Attribute
+
+	// keyed by project name, then group name
+	projectGroups map[string]map[string]*asa.ProjectGroup
+	// keyed by project name, then token ID
+	enrollmentTokens map[string]map[string]*asa.EnrollmentToken
+}
+
+// NewServer starts a fake ASA API for team that accepts the given service
+// user credentials. The API is served below URL + "/v1"; callers should
+// Close the server when done.
+func NewServer(team, keyID, keySecret string) *Server {
+	s := &Server{
+		Team:             team,
+		KeyID:            keyID,
+		KeySecret:        keySecret,
+		tokens:           map[string]bool{},
+		projects:         map[string]*asa.Project{},
+		groups:           map[string]*asa.Group{},
+		groupUsers:       map[string]map[string]bool{},
+		groupAttributes:  map[string]map[string]*asa.Attribute{},
+		users:            map[string]bool{},
+		serviceUsers:     map[string]*asa.ServiceUser{},
+		userAttributes:   map[string]map[string]*asa.Attribute{},
+		serviceUserKeys:  map[string]map[string]*asa.ServiceUserKey{},
+		projectGroups:    map[string]map[string]*asa.ProjectGroup{},
+		enrollmentTokens: map[string]map[string]*asa.EnrollmentToken{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL returns the URL to configure clients with.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// AddUser adds a user as if it had been synced from Okta.
+func (s *Server) AddUser(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[name] = true
+}
+
+// route is the parsed path of a request below /v1/teams/{team}.
+type route []string
+
+func (r route) is(pattern ...string) bool {
+	if len(r) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != r[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	prefix := "/v1/teams/" + s.Team + "/"
+	if !strings.HasPrefix(r.URL.EscapedPath(), prefix) {
+		writeError(w, http.StatusNotFound, "not_found", "unknown team")
+		return
+	}
+
+	var rt route
+	for _, segment := range strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), prefix), "/") {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+		rt = append(rt, unescaped)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rt.is("service_token") && r.Method == http.MethodPost {
+		s.serviceToken(w, r)
+		return
+	}
+
+	if !s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+		writeError(w, http.StatusUnauthorized, "authentication_error", "invalid bearer token")
+		return
+	}
+
+	switch {
+	case rt.is("projects"):
+		s.projectsCollection(w, r)
+	case rt.is("projects", "*"):
+		s.project(w, r, rt[1])
+	case rt.is("groups"):
+		s.groupsCollection(w, r)
+	case rt.is("groups", "*"):
+		s.group(w, r, rt[1])
+	case rt.is("groups", "*", "users"):
+		s.groupUsersCollection(w, r, rt[1])
+	case rt.is("groups", "*", "users", "*"):
+		s.groupUser(w, r, rt[1], rt[3])
+	case rt.is("groups", "*", "attributes"):
+		s.attributesCollection(w, r, s.groupAttributes, rt[1], s.groupExists(rt[1]))
+	case rt.is("groups", "*", "attributes", "*"):
+		s.attribute(w, r, s.groupAttributes, rt[1], rt[3])
+	case rt.is("projects", "*", "groups"):
+		s.projectGroupsCollection(w, r, rt[1])
+	case rt.is("projects", "*", "groups", "*"):
+		s.projectGroup(w, r, rt[1], rt[3])
+	case rt.is("projects", "*", "server_enrollment_tokens"):
+		s.enrollmentTokensCollection(w, r, rt[1])
+	case rt.is("projects", "*", "server_enrollment_tokens", "*"):
+		s.enrollmentToken(w, r, rt[1], rt[3])
+	case rt.is("users", "*", "attributes"):
+		s.attributesCollection(w, r, s.userAttributes, rt[1], s.userExists(rt[1]))
+	case rt.is("users", "*", "attributes", "*"):
+		s.attribute(w, r, s.userAttributes, rt[1], rt[3])
+	case rt.is("service_users"):
+		s.serviceUsersCollection(w, r)
+	case rt.is("service_users", "*"):
+		s.serviceUser(w, r, rt[1])
+	case rt.is("service_users", "*", "keys"):
+		s.serviceUserKeysCollection(w, r, rt[1])
+	case rt.is("service_users", "*", "keys", "*"):
+		s.serviceUserKey(w, r, rt[1], rt[3])
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	}
+}
+
+func (s *Server) serviceToken(w http.ResponseWriter, r *http.Request) {
+	var creds struct {
+		KeyID     string `json:"key_id"`
+		KeySecret string `json:"key_secret"`
+	}
+	if !decode(w, r, &creds) {
+		return
+	}
+	if creds.KeyID != s.KeyID || creds.KeySecret != s.KeySecret {
+		writeError(w, http.StatusUnauthorized, "authentication_error", "invalid key or secret")
+		return
+	}
+
+	token := s.newID("token")
+	s.tokens[token] = true
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"bearer_token": token,
+		"expires_at":   time.Now().Add(time.Hour).UTC(),
+		"team_name":    s.Team,
+	})
+}
+
+func (s *Server) projectsCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, name := range sortedKeys(s.projects) {
+			if !s.projects[name].Deleted() {
+				list = append(list, s.projects[name])
+			}
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		var project asa.Project
+		if !decode(w, r, &project) {
+			return
+		}
+		if existing, ok := s.projects[project.Name]; ok && !existing.Deleted() {
+			writeError(w, http.StatusConflict, "conflict", "project already exists")
+			return
+		}
+		project.DeletedAt = ""
+		if project.SSHCertificateType == "" {
+			project.SSHCertificateType = asa.SSHCertificateTypeED25519
+		}
+		s.projects[project.Name] = &project
+		delete(s.projectGroups, project.Name)
+		delete(s.enrollmentTokens, project.Name)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) project(w http.ResponseWriter, r *http.Request, name string) {
+	project, ok := s.projects[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "project not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, project)
+	case http.MethodPut:
+		if project.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "project not found")
+			return
+		}
+		var update asa.Project
+		if !decode(w, r, &update) {
+			return
+		}
+		update.Name = name
+		if update.SSHCertificateType == "" {
+			update.SSHCertificateType = project.SSHCertificateType
+		}
+		s.projects[name] = &update
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if project.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "project not found")
+			return
+		}
+		project.DeletedAt = now()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) groupsCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, name := range sortedKeys(s.groups) {
+			if !s.groups[name].Deleted() {
+				list = append(list, s.groups[name])
+			}
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		var group asa.Group
+		if !decode(w, r, &group) {
+			return
+		}
+		if existing, ok := s.groups[group.Name]; ok && !existing.Deleted() {
+			writeError(w, http.StatusConflict, "conflict", "group already exists")
+			return
+		}
+		if !validRoles(w, group.Roles) {
+			return
+		}
+		if group.Roles == nil {
+			group.Roles = []string{}
+		}
+		group.DeletedAt = ""
+		s.groups[group.Name] = &group
+		delete(s.groupUsers, group.Name)
+		delete(s.groupAttributes, group.Name)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) group(w http.ResponseWriter, r *http.Request, name string) {
+	group, ok := s.groups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "group not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, group)
+	case http.MethodPut:
+		if group.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "group not found")
+			return
+		}
+		var update asa.Group
+		if !decode(w, r, &update) || !validRoles(w, update.Roles) {
+			return
+		}
+		if update.Roles == nil {
+			update.Roles = []string{}
+		}
+		group.Roles = update.Roles
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if group.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "group not found")
+			return
+		}
+		group.DeletedAt = now()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) groupUsersCollection(w http.ResponseWriter, r *http.Request, groupName string) {
+	if !s.groupExists(groupName) {
+		writeError(w, http.StatusNotFound, "not_found", "group not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, name := range sortedKeys(s.groupUsers[groupName]) {
+			list = append(list, map[string]string{"name": name})
+		}
+		writeList(w, r, list)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) groupUser(w http.ResponseWriter, r *http.Request, groupName, userName string) {
+	if !s.groupExists(groupName) {
+		writeError(w, http.StatusNotFound, "not_found", "group not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if !s.userExists(userName) {
+			writeError(w, http.StatusNotFound, "not_found", "user not found")
+			return
+		}
+		if s.groupUsers[groupName] == nil {
+			s.groupUsers[groupName] = map[string]bool{}
+		}
+		s.groupUsers[groupName][userName] = true
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if !s.groupUsers[groupName][userName] {
+			writeError(w, http.StatusNotFound, "not_found", "user is not a member of the group")
+			return
+		}
+		delete(s.groupUsers[groupName], userName)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) projectGroupsCollection(w http.ResponseWriter, r *http.Request, projectName string) {
+	if !s.liveProject(w, projectName) {
+		return
+	}
+	assignments := s.projectGroups[projectName]
+
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, name := range sortedKeys(assignments) {
+			if !assignments[name].Deleted() {
+				list = append(list, assignments[name])
+			}
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		var assignment asa.ProjectGroup
+		if !decode(w, r, &assignment) {
+			return
+		}
+		if group, ok := s.groups[assignment.Group]; !ok || group.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "group not found")
+			return
+		}
+		if existing, ok := assignments[assignment.Group]; ok && !existing.Deleted() {
+			writeError(w, http.StatusConflict, "conflict", "group is already assigned to the project")
+			return
+		}
+		if assignments == nil {
+			assignments = map[string]*asa.ProjectGroup{}
+			s.projectGroups[projectName] = assignments
+		}
+		assignment.DeletedAt, assignment.RemovedAt = "", ""
+		assignments[assignment.Group] = &assignment
+		w.WriteHeader(http.StatusCreated)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) projectGroup(w http.ResponseWriter, r *http.Request, projectName, groupName string) {
+	if !s.liveProject(w, projectName) {
+		return
+	}
+	assignment, ok := s.projectGroups[projectName][groupName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "group is not assigned to the project")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, assignment)
+	case http.MethodPut:
+		if assignment.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "group is not assigned to the project")
+			return
+		}
+		var update asa.ProjectGroup
+		if !decode(w, r, &update) {
+			return
+		}
+		assignment.ServerAccess = update.ServerAccess
+		assignment.ServerAdmin = update.ServerAdmin
+		assignment.CreateServerGroup = update.CreateServerGroup
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if assignment.Deleted() {
+			writeError(w, http.StatusNotFound, "not_found", "group is not assigned to the project")
+			return
+		}
+		assignment.RemovedAt = now()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) enrollmentTokensCollection(w http.ResponseWriter, r *http.Request, projectName string) {
+	if !s.liveProject(w, projectName) {
+		return
+	}
+	tokens := s.enrollmentTokens[projectName]
+
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, id := range sortedKeys(tokens) {
+			list = append(list, tokens[id])
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		var token asa.EnrollmentToken
+		if !decode(w, r, &token) {
+			return
+		}
+		if tokens == nil {
+			tokens = map[string]*asa.EnrollmentToken{}
+			s.enrollmentTokens[projectName] = tokens
+		}
+		token.ID = s.newID("enrollment-token")
+		token.Token = s.newID("enrollment-token-value")
+		token.IssuedAt = now()
+		tokens[token.ID] = &token
+		writeJSON(w, http.StatusCreated, token)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) enrollmentToken(w http.ResponseWriter, r *http.Request, projectName, id string) {
+	if !s.liveProject(w, projectName) {
+		return
+	}
+	token, ok := s.enrollmentTokens[projectName][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "enrollment token not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, token)
+	case http.MethodDelete:
+		delete(s.enrollmentTokens[projectName], id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) serviceUsersCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, name := range sortedKeys(s.serviceUsers) {
+			list = append(list, s.serviceUsers[name])
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		var user asa.ServiceUser
+		if !decode(w, r, &user) {
+			return
+		}
+		if _, ok := s.serviceUsers[user.Name]; ok {
+			writeError(w, http.StatusConflict, "conflict", "service user already exists")
+			return
+		}
+		user.ID = s.newID("service-user")
+		user.Status = asa.UserStatusActive
+		s.serviceUsers[user.Name] = &user
+		writeJSON(w, http.StatusCreated, user)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) serviceUser(w http.ResponseWriter, r *http.Request, name string) {
+	user, ok := s.serviceUsers[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "service user not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, user)
+	case http.MethodPut:
+		var update asa.ServiceUser
+		if !decode(w, r, &update) {
+			return
+		}
+		if update.Status != asa.UserStatusActive && update.Status != asa.UserStatusDisabled {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid status "+update.Status)
+			return
+		}
+		user.Status = update.Status
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) serviceUserKeysCollection(w http.ResponseWriter, r *http.Request, userName string) {
+	if _, ok := s.serviceUsers[userName]; !ok {
+		writeError(w, http.StatusNotFound, "not_found", "service user not found")
+		return
+	}
+	keys := s.serviceUserKeys[userName]
+
+	switch r.Method {
+	case http.MethodGet:
+		// secrets are only returned on creation
+		var list []interface{}
+		for _, id := range sortedKeys(keys) {
+			list = append(list, asa.ServiceUserKey{ID: id, IssuedAt: keys[id].IssuedAt})
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		if keys == nil {
+			keys = map[string]*asa.ServiceUserKey{}
+			s.serviceUserKeys[userName] = keys
+		}
+		key := &asa.ServiceUserKey{
+			ID:       s.newID("service-user-key"),
+			Secret:   s.newID("service-user-secret"),
+			IssuedAt: now(),
+		}
+		keys[key.ID] = key
+		writeJSON(w, http.StatusCreated, key)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) serviceUserKey(w http.ResponseWriter, r *http.Request, userName, id string) {
+	if _, ok := s.serviceUserKeys[userName][id]; !ok {
+		writeError(w, http.StatusNotFound, "not_found", "service user key not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		delete(s.serviceUserKeys[userName], id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+// userExists reports whether name is a user, including service users.
+func (s *Server) userExists(name string) bool {
+	_, ok := s.serviceUsers[name]
+	return ok || s.users[name]
+}
+
+// groupExists reports whether name is a group that was not deleted.
+func (s *Server) groupExists(name string) bool {
+	group, ok := s.groups[name]
+	return ok && !group.Deleted()
+}
+
+// attributesCollection serves the attributes of owner, a user or a group, out
+// of attributes. An attribute value can only be used by one owner.
+func (s *Server) attributesCollection(w http.ResponseWriter, r *http.Request, attributes map[string]map[string]*asa.Attribute, owner string, exists bool) {
+	if !exists {
+		writeError(w, http.StatusNotFound, "not_found", owner+" not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var list []interface{}
+		for _, id := range sortedKeys(attributes[owner]) {
+			list = append(list, attributes[owner][id])
+		}
+		writeList(w, r, list)
+	case http.MethodPost:
+		var attribute asa.Attribute
+		if !decode(w, r, &attribute) {
+			return
+		}
+		for _, existing := range attributes[owner] {
+			if existing.Name == attribute.Name {
+				writeError(w, http.StatusConflict, "conflict", attribute.Name+" is already set")
+				return
+			}
+		}
+		if !attributeAvailable(w, attributes, owner, attribute) {
+			return
+		}
+		if attributes[owner] == nil {
+			attributes[owner] = map[string]*asa.Attribute{}
+		}
+		attribute.ID = s.newID("attribute")
+		attributes[owner][attribute.ID] = &attribute
+		writeJSON(w, http.StatusCreated, attribute)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+func (s *Server) attribute(w http.ResponseWriter, r *http.Request, attributes map[string]map[string]*asa.Attribute, owner, id string) {
+	attribute, ok := attributes[owner][id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "attribute not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var update asa.Attribute
+		if !decode(w, r, &update) {
+			return
+		}
+		if update.Name != attribute.Name {
+			writeError(w, http.StatusBadRequest, "bad_request", "attribute_name cannot be changed")
+			return
+		}
+		if !attributeAvailable(w, attributes, owner, update) {
+			return
+		}
+		attribute.Value = update.Value
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(attributes[owner], id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+	}
+}
+
+// attributeAvailable writes a 409 and returns false if an owner other than
+// owner already has the attribute with the same value.
+func attributeAvailable(w http.ResponseWriter, attributes map[string]map[string]*asa.Attribute, owner string, attribute asa.Attribute) bool {
+	for other, list := range attributes {
+		if other == owner {
+			continue
+		}
+		for _, existing := range list {
+			if existing.Name == attribute.Name && existing.String() == attribute.String() {
+				writeError(w, http.StatusConflict, "conflict",
+					fmt.Sprintf("%s %s is already used by %s", attribute.Name, attribute.String(), other))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validRoles writes a 400 and returns false if roles contains a role ASA
+// does not know.
+func validRoles(w http.ResponseWriter, roles []string) bool {
+	for _, role := range roles {
+		switch role {
+		case asa.RoleAccessUser, asa.RoleAccessAdmin, asa.RoleReportingUser:
+		default:
+			writeError(w, http.StatusBadRequest, "bad_request", "unknown role "+role)
+			return false
+		}
+	}
+	return true
+}
+
+// liveProject writes a 404 and returns false unless the project exists and
+// is not deleted.
+func (s *Server) liveProject(w http.ResponseWriter, name string) bool {
+	if project, ok := s.projects[name]; !ok || project.Deleted() {
+		writeError(w, http.StatusNotFound, "not_found", "project not found")
+		return false
+	}
+	return true
+}
+
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// sortedKeys returns the keys of a map with string keys in order, so lists
+// are paginated deterministically.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeList writes one page of list, honouring the count and offset query
+// parameters and linking to the next page like ASA does.
+func writeList(w http.ResponseWriter, r *http.Request, list []interface{}) {
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count <= 0 {
+		count = 100
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 || offset > len(list) {
+		offset = len(list)
+	}
+
+	end := offset + count
+	if end < len(list) {
+		next := *r.URL
+		query := next.Query()
+		query.Set("offset", strconv.Itoa(end))
+		query.Set("count", strconv.Itoa(count))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	} else {
+		end = len(list)
+	}
+
+	page := list[offset:end]
+	if page == nil {
+		page = []interface{}{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"list": page})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{"code": code, "message": message})
+}
@@ -0,0 +1,294 @@
+package asatest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func newTestClient(t *testing.T) (*Server, *asa.Client) {
+	server := NewServer("my-team", "key", "secret")
+	client := asa.NewClient(server.Team, server.KeyID, server.KeySecret, asa.WithBaseURL(server.BaseURL()))
+	return server, client
+}
+
+func TestServerRejectsBadCredentials(t *testing.T) {
+	server := NewServer("my-team", "key", "secret")
+	defer server.Close()
+
+	client := asa.NewClient("my-team", "key", "wrong", asa.WithBaseURL(server.BaseURL()))
+
+	var apiErr *asa.APIError
+	if err := client.Authenticate(context.Background()); err == nil || !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Fatalf("expected 401, got %v", err)
+	}
+}
+
+func TestServerSoftDeletes(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	if err := client.CreateProject(ctx, asa.Project{Name: "p"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateGroup(ctx, asa.Group{Name: "g"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateProjectGroup(ctx, "p", asa.ProjectGroup{Group: "g", ServerAccess: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateProjectGroup(ctx, "p", asa.ProjectGroup{Group: "g"}); !asa.IsConflict(err) {
+		t.Fatalf("expected conflict for a second assignment, got %v", err)
+	}
+	if err := client.UpdateProjectGroup(ctx, "p", asa.ProjectGroup{Group: "g", ServerAccess: true, ServerAdmin: true}); err != nil {
+		t.Fatal(err)
+	}
+	if assignment, err := client.GetProjectGroup(ctx, "p", "g"); err != nil || !assignment.ServerAdmin {
+		t.Fatalf("expected updated assignment, got %+v, %v", assignment, err)
+	}
+
+	if err := client.DeleteProjectGroup(ctx, "p", "g"); err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := client.GetProjectGroup(ctx, "p", "g")
+	if err != nil || !assignment.Deleted() || assignment.RemovedAt == "" {
+		t.Fatalf("expected removed assignment, got %+v, %v", assignment, err)
+	}
+
+	if err := client.DeleteGroup(ctx, "g"); err != nil {
+		t.Fatal(err)
+	}
+	group, err := client.GetGroup(ctx, "g")
+	if err != nil || !group.Deleted() {
+		t.Fatalf("expected deleted group, got %+v, %v", group, err)
+	}
+
+	if err := client.DeleteProject(ctx, "p"); err != nil {
+		t.Fatal(err)
+	}
+	project, err := client.GetProject(ctx, "p")
+	if err != nil || !project.Deleted() {
+		t.Fatalf("expected deleted project, got %+v, %v", project, err)
+	}
+
+	if _, err := client.GetProject(ctx, "never-existed"); !asa.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestServerGroupRoles(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	if err := client.CreateGroup(ctx, asa.Group{Name: "g", Roles: []string{asa.RoleAccessUser}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UpdateGroup(ctx, asa.Group{Name: "g", Roles: []string{asa.RoleAccessAdmin, asa.RoleReportingUser}}); err != nil {
+		t.Fatal(err)
+	}
+
+	group, err := client.GetGroup(ctx, "g")
+	if err != nil || len(group.Roles) != 2 || group.Roles[0] != asa.RoleAccessAdmin {
+		t.Fatalf("expected updated roles, got %+v, %v", group, err)
+	}
+
+	var apiErr *asa.APIError
+	if err := client.UpdateGroup(ctx, asa.Group{Name: "g", Roles: []string{"superuser"}}); !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		t.Fatalf("expected 400 for an unknown role, got %v", err)
+	}
+}
+
+func TestServerServiceUsers(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	user, err := client.CreateServiceUser(ctx, asa.ServiceUser{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID == "" || user.Status != asa.UserStatusActive {
+		t.Fatalf("expected an active user with an ID, got %+v", user)
+	}
+	if _, err := client.CreateServiceUser(ctx, asa.ServiceUser{Name: "ci"}); !asa.IsConflict(err) {
+		t.Fatalf("expected conflict for a second user, got %v", err)
+	}
+
+	if err := client.UpdateServiceUser(ctx, asa.ServiceUser{Name: "ci", Status: asa.UserStatusDisabled}); err != nil {
+		t.Fatal(err)
+	}
+	if user, err = client.GetServiceUser(ctx, "ci"); err != nil || user.Status != asa.UserStatusDisabled {
+		t.Fatalf("expected disabled user, got %+v, %v", user, err)
+	}
+
+	key, err := client.CreateServiceUserKey(ctx, "ci")
+	if err != nil || key.ID == "" || key.Secret == "" {
+		t.Fatalf("expected a key with a secret, got %+v, %v", key, err)
+	}
+	found, err := client.FindServiceUserKey(ctx, "ci", key.ID)
+	if err != nil || found == nil || found.Secret != "" {
+		t.Fatalf("expected the key without its secret, got %+v, %v", found, err)
+	}
+	if err := client.DeleteServiceUserKey(ctx, "ci", key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := client.FindServiceUserKey(ctx, "ci", key.ID); err != nil || found != nil {
+		t.Fatalf("expected the key to be revoked, got %+v, %v", found, err)
+	}
+}
+
+func TestServerUserAttributes(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	server.AddUser("alice")
+	server.AddUser("bob")
+
+	uid := asa.Attribute{Name: asa.AttributeUnixUID, Value: 60200}
+	if err := client.CreateUserAttribute(ctx, "alice", uid); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateUserAttribute(ctx, "bob", uid); !asa.IsConflict(err) {
+		t.Fatalf("expected conflict for a UID in use, got %v", err)
+	}
+
+	attributes, err := client.UserAttributes(ctx, "alice")
+	if err != nil || len(attributes) != 1 || attributes[0].String() != "60200" {
+		t.Fatalf("expected the UID attribute, got %+v, %v", attributes, err)
+	}
+
+	attributes[0].Value = 60201
+	if err := client.UpdateUserAttribute(ctx, "alice", attributes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateUserAttribute(ctx, "bob", uid); err != nil {
+		t.Fatalf("expected the old UID to be free, got %v", err)
+	}
+
+	if err := client.DeleteUserAttribute(ctx, "alice", attributes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if attributes, err := client.UserAttributes(ctx, "alice"); err != nil || len(attributes) != 0 {
+		t.Fatalf("expected no attributes, got %+v, %v", attributes, err)
+	}
+
+	if _, err := client.UserAttributes(ctx, "carol"); !asa.IsNotFound(err) {
+		t.Fatalf("expected not found for an unknown user, got %v", err)
+	}
+}
+
+func TestServerGroupAttributes(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	if err := client.CreateGroup(ctx, asa.Group{Name: "g"}); err != nil {
+		t.Fatal(err)
+	}
+
+	gid := asa.Attribute{Name: asa.AttributeUnixGID, Value: 60300}
+	if err := client.CreateGroupAttribute(ctx, "g", gid); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateUserAttribute(ctx, "g", gid); !asa.IsNotFound(err) {
+		t.Fatalf("expected group attributes to be separate from users, got %v", err)
+	}
+
+	// a deleted group loses its attributes when it is created again
+	if err := client.DeleteGroup(ctx, "g"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GroupAttributes(ctx, "g"); !asa.IsNotFound(err) {
+		t.Fatalf("expected not found for a deleted group, got %v", err)
+	}
+	if err := client.CreateGroup(ctx, asa.Group{Name: "g"}); err != nil {
+		t.Fatal(err)
+	}
+	if attributes, err := client.GroupAttributes(ctx, "g"); err != nil || len(attributes) != 0 {
+		t.Fatalf("expected no attributes, got %+v, %v", attributes, err)
+	}
+}
+
+func TestServerGroupUsers(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	server.AddUser("alice")
+	if err := client.CreateGroup(ctx, asa.Group{Name: "g"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddGroupUser(ctx, "g", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddGroupUser(ctx, "g", "bob"); !asa.IsNotFound(err) {
+		t.Fatalf("expected not found for an unknown user, got %v", err)
+	}
+	if names, err := client.GroupUserNames(ctx, "g"); err != nil || !reflect.DeepEqual(names, []string{"alice"}) {
+		t.Fatalf("expected alice, got %v, %v", names, err)
+	}
+
+	if err := client.RemoveGroupUser(ctx, "g", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveGroupUser(ctx, "g", "alice"); !asa.IsNotFound(err) {
+		t.Fatalf("expected not found for a removed member, got %v", err)
+	}
+	if names, err := client.GroupUserNames(ctx, "g"); err != nil || len(names) != 0 {
+		t.Fatalf("expected no members, got %v, %v", names, err)
+	}
+}
+
+func TestServerEnrollmentTokens(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	if err := client.CreateProject(ctx, asa.Project{Name: "p"}); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.CreateEnrollmentToken(ctx, "p", asa.EnrollmentToken{Description: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.Token == "" || created.Description != "d" {
+		t.Fatalf("unexpected token: %+v", created)
+	}
+
+	if err := client.DeleteEnrollmentToken(ctx, "p", created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetEnrollmentToken(ctx, "p", created.ID); !asa.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestServerPaginates(t *testing.T) {
+	server, client := newTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 250; i++ {
+		if err := client.CreateGroup(ctx, asa.Group{Name: fmt.Sprintf("group-%03d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n := 0
+	it := client.Groups()
+	for it.Next(ctx) {
+		n++
+	}
+	if it.Err() != nil || n != 250 {
+		t.Fatalf("expected 250 groups, got %d, %v", n, it.Err())
+	}
+}
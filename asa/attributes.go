@@ -0,0 +1,92 @@
+package asa
+
+import (
+	"context"
+	"fmt"
+)
+
+// Names of the attributes ASA uses to name accounts and groups on servers.
+const (
+	AttributeUnixUserName    = "unix_user_name"
+	AttributeUnixUID         = "unix_uid"
+	AttributeWindowsUserName = "windows_user_name"
+
+	AttributeUnixGroupName    = "unix_group_name"
+	AttributeUnixGID          = "unix_gid"
+	AttributeWindowsGroupName = "windows_group_name"
+)
+
+// Attribute is a user or group attribute. Value is a string or, for IDs, a
+// number.
+type Attribute struct {
+	ID    string      `json:"id,omitempty"`
+	Name  string      `json:"attribute_name"`
+	Value interface{} `json:"attribute_value"`
+}
+
+// String returns the attribute value as a string.
+func (a *Attribute) String() string {
+	if f, ok := a.Value.(float64); ok {
+		return fmt.Sprintf("%.0f", f)
+	}
+	return fmt.Sprint(a.Value)
+}
+
+// UserAttributes returns the attributes of the named user.
+func (c *Client) UserAttributes(ctx context.Context, user string) ([]Attribute, error) {
+	return c.listAttributes(ctx, c.teamPath("users", user, "attributes"))
+}
+
+// CreateUserAttribute adds an attribute to the named user.
+func (c *Client) CreateUserAttribute(ctx context.Context, user string, attribute Attribute) error {
+	return c.do(ctx, "POST", c.teamPath("users", user, "attributes"), attribute, nil)
+}
+
+// UpdateUserAttribute changes the value of the attribute with ID attribute.ID
+// of the named user.
+func (c *Client) UpdateUserAttribute(ctx context.Context, user string, attribute Attribute) error {
+	return c.do(ctx, "PUT", c.teamPath("users", user, "attributes", attribute.ID), attribute, nil)
+}
+
+// DeleteUserAttribute removes the attribute with the given ID from the named
+// user.
+func (c *Client) DeleteUserAttribute(ctx context.Context, user, id string) error {
+	return c.do(ctx, "DELETE", c.teamPath("users", user, "attributes", id), nil, nil)
+}
+
+// GroupAttributes returns the attributes of the named group.
+func (c *Client) GroupAttributes(ctx context.Context, group string) ([]Attribute, error) {
+	return c.listAttributes(ctx, c.teamPath("groups", group, "attributes"))
+}
+
+// CreateGroupAttribute adds an attribute to the named group.
+func (c *Client) CreateGroupAttribute(ctx context.Context, group string, attribute Attribute) error {
+	return c.do(ctx, "POST", c.teamPath("groups", group, "attributes"), attribute, nil)
+}
+
+// UpdateGroupAttribute changes the value of the attribute with ID
+// attribute.ID of the named group.
+func (c *Client) UpdateGroupAttribute(ctx context.Context, group string, attribute Attribute) error {
+	return c.do(ctx, "PUT", c.teamPath("groups", group, "attributes", attribute.ID), attribute, nil)
+}
+
+// DeleteGroupAttribute removes the attribute with the given ID from the named
+// group.
+func (c *Client) DeleteGroupAttribute(ctx context.Context, group, id string) error {
+	return c.do(ctx, "DELETE", c.teamPath("groups", group, "attributes", id), nil, nil)
+}
+
+func (c *Client) listAttributes(ctx context.Context, path string) ([]Attribute, error) {
+	var attributes []Attribute
+
+	it := c.iterate(path)
+	for it.Next(ctx) {
+		var attribute Attribute
+		if err := it.Decode(&attribute); err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, attribute)
+	}
+
+	return attributes, it.Err()
+}
@@ -0,0 +1,222 @@
+// Package asa is a client for the Okta Advanced Server Access (ASA) API.
+//
+// It covers the team-scoped endpoints used by the Terraform provider:
+// projects, groups, project groups, server enrollment tokens, service
+// users and their API keys, user and group attributes, and group members.
+package asa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/resty.v1"
+)
+
+// DefaultBaseURL is the ASA API endpoint used when no other is configured.
+const DefaultBaseURL = "https://app.scaleft.com/v1"
+
+// tokenRefreshWindow is how long before its expiry a bearer token is renewed.
+const tokenRefreshWindow = time.Minute
+
+// Client talks to the ASA API on behalf of a single team. Each Client has its
+// own HTTP client and bearer token, so clients for different teams can be
+// used side by side.
+type Client struct {
+	baseURL   string
+	team      string
+	keyID     string
+	keySecret string
+
+	http       *resty.Client
+	maxRetries int
+	maxWait    time.Duration
+	limiter    *rate.Limiter
+	inFlight   chan struct{}
+
+	mu          sync.Mutex
+	bearerToken string
+	expiresAt   time.Time
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// NewClient returns a Client for team that authenticates with the given
+// service user API key and secret.
+func NewClient(team, keyID, keySecret string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		team:       team,
+		keyID:      keyID,
+		keySecret:  keySecret,
+		http:       resty.New(),
+		maxRetries: DefaultMaxRetries,
+		maxWait:    DefaultMaxWait,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Team returns the name of the team the client is bound to.
+func (c *Client) Team() string {
+	return c.team
+}
+
+type serviceToken struct {
+	BearerToken string    `json:"bearer_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Authenticate exchanges the API key and secret for a bearer token that is
+// used by all subsequent requests. The client calls it on its own when the
+// token is missing, about to expire or rejected by the API.
+func (c *Client) Authenticate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.authenticate(ctx)
+}
+
+// authenticate fetches a new bearer token. The caller must hold c.mu.
+func (c *Client) authenticate(ctx context.Context) error {
+	log.Printf("[DEBUG] Getting bearer token for team %s", c.team)
+
+	credentials := map[string]string{"key_id": c.keyID, "key_secret": c.keySecret}
+
+	var token serviceToken
+	if err := c.send(ctx, "POST", c.teamPath("service_token"), "", credentials, &token); err != nil {
+		return fmt.Errorf("authenticating to team %s: %w", c.team, err)
+	}
+	if token.BearerToken == "" {
+		return fmt.Errorf("authenticating to team %s: response did not contain a bearer token", c.team)
+	}
+
+	c.bearerToken = token.BearerToken
+	c.expiresAt = token.ExpiresAt
+	return nil
+}
+
+// token returns a usable bearer token, authenticating first if there is none
+// yet, the current one expires within tokenRefreshWindow or it equals stale.
+func (c *Client) token(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiring := !c.expiresAt.IsZero() && time.Until(c.expiresAt) < tokenRefreshWindow
+	if c.bearerToken == "" || c.bearerToken == stale || expiring {
+		if err := c.authenticate(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return c.bearerToken, nil
+}
+
+// teamPath joins the escaped path segments below /teams/{team}.
+func (c *Client) teamPath(segments ...string) string {
+	path := "/teams/" + url.PathEscape(c.team)
+	for _, s := range segments {
+		path += "/" + url.PathEscape(s)
+	}
+	return path
+}
+
+// do sends an authenticated JSON request and decodes a successful response
+// into out, which may be nil. Responses outside the 2xx range are returned as
+// *APIError. A 401 response is retried once with a fresh bearer token.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	token, err := c.token(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	err = c.send(ctx, method, path, token, body, out)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		log.Printf("[DEBUG] Bearer token for team %s was rejected, re-authenticating", c.team)
+
+		if token, err = c.token(ctx, token); err != nil {
+			return err
+		}
+		err = c.send(ctx, method, path, token, body, out)
+	}
+
+	return err
+}
+
+// send performs a request with the given bearer token, which may be empty.
+// Throttled and temporarily failing requests are retried as described in
+// retryWait.
+func (c *Client) send(ctx context.Context, method, path, token string, body, out interface{}) error {
+	composedURL := c.baseURL + path
+
+	for attempt := 0; ; attempt++ {
+		req := c.http.R().
+			SetContext(ctx).
+			SetHeaders(map[string]string{
+				"Accept":       "application/json",
+				"Content-Type": "application/json"})
+
+		if token != "" {
+			req.SetAuthToken(token)
+		}
+		if body != nil {
+			req.SetBody(body)
+		}
+
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := req.Execute(method, composedURL)
+		release()
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] %s to %s. Status code: %d", method, composedURL, resp.StatusCode())
+
+		if resp.IsSuccess() {
+			if h, ok := out.(headerReader); ok {
+				h.readHeader(resp.Header())
+			}
+			if out == nil || len(resp.Body()) == 0 {
+				return nil
+			}
+			return json.Unmarshal(resp.Body(), out)
+		}
+
+		wait, retry := c.retryWait(attempt, method, path, resp)
+		if !retry {
+			return newAPIError(method, path, resp.StatusCode(), resp.Header(), resp.Body())
+		}
+
+		log.Printf("[DEBUG] %s to %s returned %d, retrying in %s (attempt %d of %d)",
+			method, composedURL, resp.StatusCode(), wait, attempt+1, c.maxRetries)
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
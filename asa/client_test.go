@@ -0,0 +1,196 @@
+package asa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientAuthenticateAndGetProject(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/teams/my-team/service_token", func(w http.ResponseWriter, r *http.Request) {
+		var creds map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			t.Fatalf("decoding credentials: %s", err)
+		}
+		if creds["key_id"] != "key" || creds["key_secret"] != "secret" {
+			t.Errorf("unexpected credentials: %v", creds)
+		}
+		w.Write([]byte(`{"bearer_token":"token-1"}`))
+	})
+	mux.HandleFunc("/v1/teams/my-team/projects/my-project", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		w.Write([]byte(`{"name":"my-project","next_unix_uid":60101,"next_unix_gid":63001}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL+"/v1"))
+	if err := client.Authenticate(context.Background()); err != nil {
+		t.Fatalf("Authenticate: %s", err)
+	}
+
+	project, err := client.GetProject(context.Background(), "my-project")
+	if err != nil {
+		t.Fatalf("GetProject: %s", err)
+	}
+	if project.Name != "my-project" || project.NextUnixUID != 60101 || project.NextUnixGID != 63001 {
+		t.Errorf("unexpected project: %+v", project)
+	}
+	if project.Deleted() {
+		t.Errorf("project should not be deleted")
+	}
+}
+
+func TestClientAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teams/my-team/service_token" {
+			w.Write([]byte(`{"bearer_token":"token"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL))
+
+	_, err := client.GetProjectGroup(context.Background(), "my-project", "my-group")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Path != "/teams/my-team/projects/my-project/groups/my-group" {
+		t.Errorf("unexpected path: %s", apiErr.Path)
+	}
+}
+
+func TestClientsAreIsolatedPerTeam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/teams/prod/service_token":
+			w.Write([]byte(`{"bearer_token":"prod-token"}`))
+		case "/teams/staging/service_token":
+			w.Write([]byte(`{"bearer_token":"staging-token"}`))
+		case "/teams/prod/groups/ops":
+			if r.Header.Get("Authorization") != "Bearer prod-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"name":"ops","roles":["access_user"]}`))
+		case "/teams/staging/groups/ops":
+			if r.Header.Get("Authorization") != "Bearer staging-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"name":"ops","roles":["access_admin"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	prod := NewClient("prod", "key", "secret", WithBaseURL(server.URL))
+	staging := NewClient("staging", "key", "secret", WithBaseURL(server.URL))
+
+	for _, c := range []*Client{prod, staging} {
+		if err := c.Authenticate(context.Background()); err != nil {
+			t.Fatalf("Authenticate(%s): %s", c.Team(), err)
+		}
+	}
+
+	want := map[*Client]string{prod: "access_user", staging: "access_admin"}
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		for c, role := range want {
+			go func(c *Client, role string) {
+				group, err := c.GetGroup(context.Background(), "ops")
+				if err == nil && group.Roles[0] != role {
+					err = errors.New(c.Team() + " got role " + group.Roles[0])
+				}
+				errs <- err
+			}(c, role)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestClientAuthenticateFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "bad-key", "bad-secret", WithBaseURL(server.URL))
+
+	err := client.Authenticate(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 *APIError, got %v", err)
+	}
+}
+
+func TestClientReauthenticates(t *testing.T) {
+	var issued, rejected int
+	expiresAt := time.Now().Add(time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/teams/my-team/service_token":
+			issued++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"bearer_token": fmt.Sprintf("token-%d", issued),
+				"expires_at":   expiresAt,
+			})
+		case "/teams/my-team/groups/ops":
+			// the first token is revoked server-side
+			if r.Header.Get("Authorization") == "Bearer token-1" {
+				rejected++
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"name":"ops","roles":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL))
+
+	if _, err := client.GetGroup(context.Background(), "ops"); err != nil {
+		t.Fatalf("GetGroup after 401: %s", err)
+	}
+	if issued != 2 || rejected != 1 {
+		t.Fatalf("expected 2 tokens and 1 rejection, got %d and %d", issued, rejected)
+	}
+
+	// a token that is about to expire is renewed before it is used
+	expiresAt = time.Now().Add(10 * time.Second)
+	if err := client.Authenticate(context.Background()); err != nil {
+		t.Fatalf("Authenticate: %s", err)
+	}
+	if _, err := client.GetGroup(context.Background(), "ops"); err != nil {
+		t.Fatalf("GetGroup with expiring token: %s", err)
+	}
+	if issued != 4 {
+		t.Fatalf("expected the expiring token to be renewed, %d tokens issued", issued)
+	}
+}
@@ -0,0 +1,36 @@
+package asa
+
+import "context"
+
+// EnrollmentToken is a server enrollment token. The ASA agent uses the
+// token value to enroll a server into the token's project.
+type EnrollmentToken struct {
+	ID          string `json:"id,omitempty"`
+	Description string `json:"description"`
+	Token       string `json:"token,omitempty"`
+	IssuedAt    string `json:"issued_at,omitempty"`
+}
+
+// CreateEnrollmentToken creates an enrollment token in project and returns
+// it with its ID and value populated.
+func (c *Client) CreateEnrollmentToken(ctx context.Context, project string, token EnrollmentToken) (*EnrollmentToken, error) {
+	var created EnrollmentToken
+	if err := c.do(ctx, "POST", c.teamPath("projects", project, "server_enrollment_tokens"), token, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetEnrollmentToken returns the enrollment token with the given ID.
+func (c *Client) GetEnrollmentToken(ctx context.Context, project, id string) (*EnrollmentToken, error) {
+	var token EnrollmentToken
+	if err := c.do(ctx, "GET", c.teamPath("projects", project, "server_enrollment_tokens", id), nil, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteEnrollmentToken deletes the enrollment token with the given ID.
+func (c *Client) DeleteEnrollmentToken(ctx context.Context, project, id string) error {
+	return c.do(ctx, "DELETE", c.teamPath("projects", project, "server_enrollment_tokens", id), nil, nil)
+}
@@ -0,0 +1,108 @@
+package asa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// requestIDHeaders are the response headers ASA and its proxies use to
+// identify a request, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
+
+// APIError is returned when the ASA API responds with a non-2xx status code.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+
+	// Code and Message are parsed from the JSON error body, when there is one.
+	Code    string
+	Message string
+
+	// RequestID identifies the request in ASA's logs. It is empty if the
+	// response did not carry a request ID header.
+	RequestID string
+
+	// Body is the raw response body.
+	Body string
+}
+
+// newAPIError builds an APIError from a failed response.
+func newAPIError(method, path string, statusCode int, header http.Header, body []byte) *APIError {
+	e := &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+
+	var payload struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		e.Code = payload.Code
+		e.Message = payload.Message
+		if e.Message == "" {
+			e.Message = payload.Error
+		}
+	}
+
+	for _, h := range requestIDHeaders {
+		if id := header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+
+	switch {
+	case e.Code != "" && e.Message != "":
+		fmt.Fprintf(&b, ": %s: %s", e.Code, e.Message)
+	case e.Message != "":
+		fmt.Fprintf(&b, ": %s", e.Message)
+	case e.Code != "":
+		fmt.Fprintf(&b, ": %s", e.Code)
+	case e.Body != "":
+		fmt.Fprintf(&b, ": %s", e.Body)
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID %s)", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// IsNotFound reports whether err is an APIError for a missing object.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError for an object that already
+// exists or was modified concurrently.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsPermissionDenied reports whether err is an APIError for a request the
+// service user is not allowed to make.
+func IsPermissionDenied(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,56 @@
+package asa
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Request-Id", "req-123")
+
+	err := newAPIError("GET", "/teams/my-team/projects/p", 404, header,
+		[]byte(`{"code":"not_found","message":"Project p does not exist"}`))
+
+	if err.Code != "not_found" || err.Message != "Project p does not exist" || err.RequestID != "req-123" {
+		t.Fatalf("unexpected error fields: %+v", err)
+	}
+
+	want := "GET /teams/my-team/projects/p: 404 Not Found: not_found: Project p does not exist (request ID req-123)"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+
+	wrapped := fmt.Errorf("reading project: %w", err)
+	if !IsNotFound(wrapped) {
+		t.Errorf("expected wrapped error to be not found")
+	}
+	if IsConflict(wrapped) || IsPermissionDenied(wrapped) {
+		t.Errorf("404 must not be reported as conflict or permission denied")
+	}
+}
+
+func TestAPIErrorWithoutJSONBody(t *testing.T) {
+	err := newAPIError("POST", "/teams/my-team/groups", 502, http.Header{}, []byte("<html>Bad Gateway</html>"))
+
+	want := "POST /teams/my-team/groups: 502 Bad Gateway: <html>Bad Gateway</html>"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestAPIErrorHelpers(t *testing.T) {
+	conflict := newAPIError("POST", "/teams/my-team/groups", 409, http.Header{}, nil)
+	forbidden := newAPIError("DELETE", "/teams/my-team/groups/g", 403, http.Header{}, nil)
+
+	if !IsConflict(conflict) {
+		t.Errorf("expected 409 to be a conflict")
+	}
+	if !IsPermissionDenied(forbidden) {
+		t.Errorf("expected 403 to be permission denied")
+	}
+	if IsNotFound(fmt.Errorf("transport error")) {
+		t.Errorf("non-API errors must not be reported as not found")
+	}
+}
@@ -0,0 +1,76 @@
+package asa
+
+import "context"
+
+// Team-level roles a group can grant its members.
+const (
+	RoleAccessUser    = "access_user"
+	RoleAccessAdmin   = "access_admin"
+	RoleReportingUser = "reporting_user"
+)
+
+// Group is an ASA group. Groups are either synced from Okta or created
+// locally in ASA.
+type Group struct {
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles"`
+	DeletedAt string   `json:"deleted_at,omitempty"`
+}
+
+// Deleted reports whether the API returned the group soft-deleted.
+func (g *Group) Deleted() bool {
+	return g.DeletedAt != ""
+}
+
+// CreateGroup creates a group in the team.
+func (c *Client) CreateGroup(ctx context.Context, group Group) error {
+	return c.do(ctx, "POST", c.teamPath("groups"), group, nil)
+}
+
+// GetGroup returns the named group.
+func (c *Client) GetGroup(ctx context.Context, name string) (*Group, error) {
+	var group Group
+	if err := c.do(ctx, "GET", c.teamPath("groups", name), nil, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// UpdateGroup replaces the roles of the group named group.Name.
+func (c *Client) UpdateGroup(ctx context.Context, group Group) error {
+	return c.do(ctx, "PUT", c.teamPath("groups", group.Name), group, nil)
+}
+
+// DeleteGroup deletes the named group.
+func (c *Client) DeleteGroup(ctx context.Context, name string) error {
+	return c.do(ctx, "DELETE", c.teamPath("groups", name), nil, nil)
+}
+
+// GroupUserNames returns the names of the members of the named group.
+func (c *Client) GroupUserNames(ctx context.Context, group string) ([]string, error) {
+	var names []string
+
+	it := c.GroupUsers(group)
+	for it.Next(ctx) {
+		var user struct {
+			Name string `json:"name"`
+		}
+		if err := it.Decode(&user); err != nil {
+			return nil, err
+		}
+		names = append(names, user.Name)
+	}
+
+	return names, it.Err()
+}
+
+// AddGroupUser adds the named user to the named group. Only groups created in
+// ASA can be changed; members of groups synced from Okta are managed in Okta.
+func (c *Client) AddGroupUser(ctx context.Context, group, user string) error {
+	return c.do(ctx, "POST", c.teamPath("groups", group, "users", user), nil, nil)
+}
+
+// RemoveGroupUser removes the named user from the named group.
+func (c *Client) RemoveGroupUser(ctx context.Context, group, user string) error {
+	return c.do(ctx, "DELETE", c.teamPath("groups", group, "users", user), nil, nil)
+}
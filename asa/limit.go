@@ -0,0 +1,49 @@
+package asa
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit caps the client at requestsPerSecond requests per second and
+// at most maxConcurrent requests in flight. A value of 0 leaves that limit
+// off. All requests made through the client share the limits, including
+// retries and token renewals.
+func WithRateLimit(requestsPerSecond, maxConcurrent int) Option {
+	return func(c *Client) {
+		c.limiter = nil
+		if requestsPerSecond > 0 {
+			c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), requestsPerSecond)
+		}
+
+		c.inFlight = nil
+		if maxConcurrent > 0 {
+			c.inFlight = make(chan struct{}, maxConcurrent)
+		}
+	}
+}
+
+// acquire blocks until the request may be sent under the client's limits.
+// The returned func must be called once the response has been read.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+			release = func() { <-c.inFlight }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
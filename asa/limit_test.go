@@ -0,0 +1,79 @@
+package asa
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientConcurrencyLimit(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teams/my-team/service_token" {
+			w.Write([]byte(`{"bearer_token":"token"}`))
+			return
+		}
+
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"name":"ops","roles":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL), WithRateLimit(0, 2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetGroup(context.Background(), "ops"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, saw %d", maxInFlight)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teams/my-team/service_token" {
+			w.Write([]byte(`{"bearer_token":"token"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"ops","roles":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL), WithRateLimit(20, 0))
+
+	// the token exchange and the first 19 requests use up the burst, the
+	// remaining 10 have to wait 50ms each
+	start := time.Now()
+	for i := 0; i < 29; i++ {
+		if _, err := client.GetGroup(context.Background(), "ops"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expected requests to be throttled, 30 requests took %s", elapsed)
+	}
+}
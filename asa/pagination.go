@@ -0,0 +1,154 @@
+package asa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// listPageSize is the number of items requested per page.
+const listPageSize = 100
+
+// linkNextPattern matches the next page URL in a Link header.
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// headerReader is implemented by response values that need the response
+// headers as well as the body.
+type headerReader interface {
+	readHeader(http.Header)
+}
+
+// page is a single page of a list endpoint.
+type page struct {
+	List []json.RawMessage `json:"list"`
+	next string
+}
+
+func (p *page) readHeader(h http.Header) {
+	p.next = ""
+	for _, link := range h["Link"] {
+		if m := linkNextPattern.FindStringSubmatch(link); m != nil {
+			p.next = m[1]
+			return
+		}
+	}
+}
+
+// Iterator walks the items of a list endpoint, fetching further pages as
+// needed by following the Link header of each response.
+//
+//	it := client.Groups()
+//	for it.Next(ctx) {
+//		var group asa.Group
+//		if err := it.Decode(&group); err != nil {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	c    *Client
+	path string
+
+	items []json.RawMessage
+	cur   json.RawMessage
+	err   error
+}
+
+// iterate returns an Iterator over the list endpoint at path.
+func (c *Client) iterate(path string) *Iterator {
+	query := url.Values{"count": {strconv.Itoa(listPageSize)}}
+	return &Iterator{c: c, path: path + "?" + query.Encode()}
+}
+
+// Next advances to the next item, fetching the next page when the current
+// one is used up. It returns false when there are no more items or a request
+// failed; Err tells the two apart.
+func (it *Iterator) Next(ctx context.Context) bool {
+	for len(it.items) == 0 {
+		if it.err != nil || it.path == "" {
+			return false
+		}
+
+		var p page
+		if it.err = it.c.do(ctx, "GET", it.path, nil, &p); it.err != nil {
+			return false
+		}
+
+		it.items = p.List
+		it.path = it.c.relativePath(p.next)
+	}
+
+	it.cur, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Decode unmarshals the current item into v.
+func (it *Iterator) Decode(v interface{}) error {
+	return json.Unmarshal(it.cur, v)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// relativePath turns an absolute next page URL into a path below the base
+// URL, keeping its query string. It returns "" for an empty link.
+func (c *Client) relativePath(link string) string {
+	if link == "" {
+		return ""
+	}
+
+	next, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(next.RequestURI(), strings.TrimSuffix(base.Path, "/"))
+}
+
+// Projects returns an Iterator over the team's projects.
+func (c *Client) Projects() *Iterator {
+	return c.iterate(c.teamPath("projects"))
+}
+
+// Groups returns an Iterator over the team's groups.
+func (c *Client) Groups() *Iterator {
+	return c.iterate(c.teamPath("groups"))
+}
+
+// GroupUsers returns an Iterator over the members of the named group.
+func (c *Client) GroupUsers(group string) *Iterator {
+	return c.iterate(c.teamPath("groups", group, "users"))
+}
+
+// ProjectGroups returns an Iterator over the groups assigned to project.
+func (c *Client) ProjectGroups(project string) *Iterator {
+	return c.iterate(c.teamPath("projects", project, "groups"))
+}
+
+// EnrollmentTokens returns an Iterator over the enrollment tokens of project.
+func (c *Client) EnrollmentTokens(project string) *Iterator {
+	return c.iterate(c.teamPath("projects", project, "server_enrollment_tokens"))
+}
+
+// ServiceUsers returns an Iterator over the team's service users.
+func (c *Client) ServiceUsers() *Iterator {
+	return c.iterate(c.teamPath("service_users"))
+}
+
+// ServiceUserKeys returns an Iterator over the API keys of the named service
+// user.
+func (c *Client) ServiceUserKeys(user string) *Iterator {
+	return c.iterate(c.teamPath("service_users", user, "keys"))
+}
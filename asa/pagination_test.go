@@ -0,0 +1,83 @@
+package asa
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestIteratorFollowsLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/teams/my-team/service_token":
+			w.Write([]byte(`{"bearer_token":"token"}`))
+		case "/v1/teams/my-team/groups":
+			if r.URL.Query().Get("count") != "100" {
+				t.Errorf("expected count=100, got %q", r.URL.RawQuery)
+			}
+
+			// three pages of two groups each
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			if offset < 4 {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/v1/teams/my-team/groups?count=100&offset=%d>; rel="next"`, server.URL, offset+2))
+			}
+			fmt.Fprintf(w, `{"list":[{"name":"group-%d","roles":[]},{"name":"group-%d","roles":[]}]}`, offset, offset+1)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL+"/v1"))
+
+	var names []string
+	it := client.Groups()
+	for it.Next(context.Background()) {
+		var group Group
+		if err := it.Decode(&group); err != nil {
+			t.Fatalf("Decode: %s", err)
+		}
+		names = append(names, group.Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %s", err)
+	}
+
+	if len(names) != 6 || names[0] != "group-0" || names[5] != "group-5" {
+		t.Errorf("unexpected groups: %v", names)
+	}
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/teams/my-team/service_token":
+			w.Write([]byte(`{"bearer_token":"token"}`))
+		case r.URL.Query().Get("offset") == "":
+			w.Header().Set("Link", `</teams/my-team/projects?offset=p1>; rel="next"`)
+			w.Write([]byte(`{"list":[{"name":"p0"}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL))
+
+	n := 0
+	it := client.Projects()
+	for it.Next(context.Background()) {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("expected 1 project before the error, got %d", n)
+	}
+	if !IsPermissionDenied(it.Err()) {
+		t.Errorf("expected a permission denied error, got %v", it.Err())
+	}
+}
@@ -0,0 +1,44 @@
+package asa
+
+import "context"
+
+// ProjectGroup is the assignment of a group to a project and the
+// permissions its members get on the project's servers.
+type ProjectGroup struct {
+	Group             string `json:"group"`
+	ServerAccess      bool   `json:"server_access"`
+	ServerAdmin       bool   `json:"server_admin"`
+	CreateServerGroup bool   `json:"create_server_group"`
+	DeletedAt         string `json:"deleted_at,omitempty"`
+	RemovedAt         string `json:"removed_at,omitempty"`
+}
+
+// Deleted reports whether the API returned the assignment soft-deleted.
+func (g *ProjectGroup) Deleted() bool {
+	return g.DeletedAt != "" || g.RemovedAt != ""
+}
+
+// CreateProjectGroup assigns group.Group to project.
+func (c *Client) CreateProjectGroup(ctx context.Context, project string, group ProjectGroup) error {
+	return c.do(ctx, "POST", c.teamPath("projects", project, "groups"), group, nil)
+}
+
+// GetProjectGroup returns the assignment of group to project.
+func (c *Client) GetProjectGroup(ctx context.Context, project, group string) (*ProjectGroup, error) {
+	var projectGroup ProjectGroup
+	if err := c.do(ctx, "GET", c.teamPath("projects", project, "groups", group), nil, &projectGroup); err != nil {
+		return nil, err
+	}
+	return &projectGroup, nil
+}
+
+// UpdateProjectGroup changes the permissions of the assignment of
+// group.Group to project.
+func (c *Client) UpdateProjectGroup(ctx context.Context, project string, group ProjectGroup) error {
+	return c.do(ctx, "PUT", c.teamPath("projects", project, "groups", group.Group), group, nil)
+}
+
+// DeleteProjectGroup removes group from project.
+func (c *Client) DeleteProjectGroup(ctx context.Context, project, group string) error {
+	return c.do(ctx, "DELETE", c.teamPath("projects", project, "groups", group), nil, nil)
+}
@@ -0,0 +1,65 @@
+package asa
+
+import "context"
+
+// SSH certificate types a project can issue.
+const (
+	SSHCertificateTypeED25519 = "CERT_TYPE_ED25519_01"
+	SSHCertificateTypeRSA     = "CERT_TYPE_RSA_01"
+)
+
+// Project is an ASA authorization scope that servers are enrolled into.
+type Project struct {
+	Name                    string `json:"name"`
+	NextUnixUID             int    `json:"next_unix_uid,omitempty"`
+	NextUnixGID             int    `json:"next_unix_gid,omitempty"`
+	CreateServerUsers       bool   `json:"create_server_users"`
+	RequirePreauthorization bool   `json:"require_preauthorization"`
+	ForceSharedSSHUsers     bool   `json:"force_shared_ssh_users"`
+	SSHCertificateType      string `json:"ssh_certificate_type,omitempty"`
+	DeletedAt               string `json:"deleted_at,omitempty"`
+
+	// ForwardTraffic routes connections through the gateways whose labels
+	// match GatewaySelector, such as "env=prod,region=us-east-1". Session
+	// recording requires it.
+	ForwardTraffic      bool   `json:"forward_traffic"`
+	GatewaySelector     string `json:"gateway_selector"`
+	SSHSessionRecording bool   `json:"ssh_session_recording"`
+	RDPSessionRecording bool   `json:"rdp_session_recording"`
+
+	// UserOnDemandPeriod is how many hours users created on demand stay on
+	// a server. Nil means they are not removed.
+	UserOnDemandPeriod *int `json:"user_on_demand_period"`
+	// SharedAdminUserName is the name of the admin account shared by users
+	// with server_admin. Nil means every user gets an account of their own.
+	SharedAdminUserName *string `json:"shared_admin_user_name"`
+}
+
+// Deleted reports whether the API returned the project soft-deleted.
+func (p *Project) Deleted() bool {
+	return p.DeletedAt != ""
+}
+
+// CreateProject creates a project in the team.
+func (c *Client) CreateProject(ctx context.Context, project Project) error {
+	return c.do(ctx, "POST", c.teamPath("projects"), project, nil)
+}
+
+// GetProject returns the named project.
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	var project Project
+	if err := c.do(ctx, "GET", c.teamPath("projects", name), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// UpdateProject replaces the settings of the project named project.Name.
+func (c *Client) UpdateProject(ctx context.Context, project Project) error {
+	return c.do(ctx, "PUT", c.teamPath("projects", project.Name), project, nil)
+}
+
+// DeleteProject deletes the named project.
+func (c *Client) DeleteProject(ctx context.Context, name string) error {
+	return c.do(ctx, "DELETE", c.teamPath("projects", name), nil, nil)
+}
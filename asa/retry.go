@@ -0,0 +1,108 @@
+package asa
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopkg.in/resty.v1"
+)
+
+const (
+	// DefaultMaxRetries is how many times a throttled or temporarily
+	// failing request is retried when no other limit is configured.
+	DefaultMaxRetries = 5
+
+	// DefaultMaxWait is the longest the client waits before a single retry
+	// when no other limit is configured.
+	DefaultMaxWait = 30 * time.Second
+
+	// baseRetryWait is the first backoff interval when the API does not
+	// send a Retry-After header. It doubles with each attempt.
+	baseRetryWait = time.Second
+)
+
+// WithRetry sets how many times a request that was throttled (429) or hit a
+// gateway error (502, 503, 504) is retried, and the longest wait before a
+// single retry. A maxRetries of 0 disables retries.
+func WithRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.maxWait = maxWait
+	}
+}
+
+// retryWait reports whether the failed request should be retried and how
+// long to wait first. The Retry-After header is honoured when present;
+// otherwise the wait doubles with every attempt, up to c.maxWait.
+//
+// A POST that creates an object is only retried on 429, since ASA rejects
+// throttled requests before doing any work. A gateway error may arrive after
+// the object was created, and repeating the POST could create it twice.
+func (c *Client) retryWait(attempt int, method, path string, resp *resty.Response) (time.Duration, bool) {
+	if attempt >= c.maxRetries {
+		return 0, false
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests:
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !c.idempotent(method, path) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if wait, ok := retryAfter(resp.Header().Get("Retry-After")); ok {
+		// waiting less than the server asked for would only be throttled again
+		return wait, wait <= c.maxWait
+	}
+
+	wait := baseRetryWait << uint(attempt)
+	if wait > c.maxWait || wait <= 0 {
+		wait = c.maxWait
+	}
+	return wait, true
+}
+
+// idempotent reports whether sending the request twice has the same effect
+// as sending it once. Exchanging credentials for a bearer token is a POST
+// without side effects.
+func (c *Client) idempotent(method, path string) bool {
+	return method != http.MethodPost || path == c.teamPath("service_token")
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for d, returning early with the context's error if it is
+// cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
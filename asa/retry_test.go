@@ -0,0 +1,117 @@
+package asa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newRetryTestServer returns a server that answers requests to path with the
+// given status codes in turn, and 200 once they are used up.
+func newRetryTestServer(t *testing.T, path string, statuses ...int) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teams/my-team/service_token" {
+			w.Write([]byte(`{"bearer_token":"token"}`))
+			return
+		}
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		if calls <= len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[calls-1])
+			return
+		}
+		w.Write([]byte(`{"name":"ops","roles":[]}`))
+	}))
+	return server, &calls
+}
+
+func TestClientRetriesTemporaryFailures(t *testing.T) {
+	server, calls := newRetryTestServer(t, "/teams/my-team/groups/ops", 429, 502, 503, 504)
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL))
+
+	if _, err := client.GetGroup(context.Background(), "ops"); err != nil {
+		t.Fatalf("GetGroup: %s", err)
+	}
+	if *calls != 5 {
+		t.Errorf("expected 5 calls, got %d", *calls)
+	}
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := newRetryTestServer(t, "/teams/my-team/groups/ops", 429, 429, 429, 429)
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL), WithRetry(2, time.Second))
+
+	_, err := client.GetGroup(context.Background(), "ops")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 *APIError, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 calls, got %d", *calls)
+	}
+}
+
+func TestClientDoesNotRepeatCreateAfterGatewayError(t *testing.T) {
+	server, calls := newRetryTestServer(t, "/teams/my-team/groups", 502)
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL))
+
+	err := client.CreateGroup(context.Background(), Group{Name: "ops"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 *APIError, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 call, got %d", *calls)
+	}
+}
+
+func TestClientRetriesThrottledCreate(t *testing.T) {
+	server, calls := newRetryTestServer(t, "/teams/my-team/groups", 429)
+	defer server.Close()
+
+	client := NewClient("my-team", "key", "secret", WithBaseURL(server.URL))
+
+	if err := client.CreateGroup(context.Background(), Group{Name: "ops"}); err != nil {
+		t.Fatalf("CreateGroup: %s", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		header string
+		wait   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+	}
+
+	for _, tc := range cases {
+		wait, ok := retryAfter(tc.header)
+		if wait != tc.wait || ok != tc.ok {
+			t.Errorf("retryAfter(%q) = %s, %t; want %s, %t", tc.header, wait, ok, tc.wait, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package asa
+
+import "context"
+
+// ServiceUserKey is an API key of a service user. The secret is only
+// returned when the key is created.
+type ServiceUserKey struct {
+	ID       string `json:"id"`
+	Secret   string `json:"secret,omitempty"`
+	IssuedAt string `json:"issued_at,omitempty"`
+}
+
+// CreateServiceUserKey issues a new API key for the named service user.
+func (c *Client) CreateServiceUserKey(ctx context.Context, user string) (*ServiceUserKey, error) {
+	var key ServiceUserKey
+	if err := c.do(ctx, "POST", c.teamPath("service_users", user, "keys"), nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindServiceUserKey returns the API key of the named service user with the
+// given ID, or nil if the user has no such key. ASA has no endpoint for a
+// single key, so the user's keys are listed.
+func (c *Client) FindServiceUserKey(ctx context.Context, user, id string) (*ServiceUserKey, error) {
+	keys := c.ServiceUserKeys(user)
+	for keys.Next(ctx) {
+		var key ServiceUserKey
+		if err := keys.Decode(&key); err != nil {
+			return nil, err
+		}
+		if key.ID == id {
+			return &key, nil
+		}
+	}
+	return nil, keys.Err()
+}
+
+// DeleteServiceUserKey revokes the API key of the named service user with
+// the given ID.
+func (c *Client) DeleteServiceUserKey(ctx context.Context, user, id string) error {
+	return c.do(ctx, "DELETE", c.teamPath("service_users", user, "keys", id), nil, nil)
+}
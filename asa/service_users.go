@@ -0,0 +1,43 @@
+package asa
+
+import "context"
+
+// Statuses of an ASA user.
+const (
+	UserStatusActive   = "ACTIVE"
+	UserStatusDisabled = "DISABLED"
+	UserStatusDeleted  = "DELETED"
+)
+
+// ServiceUser is an ASA user for automation. It authenticates with API keys
+// rather than through Okta.
+type ServiceUser struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+}
+
+// CreateServiceUser creates a service user in the team and returns it with
+// its ID and status populated.
+func (c *Client) CreateServiceUser(ctx context.Context, user ServiceUser) (*ServiceUser, error) {
+	var created ServiceUser
+	if err := c.do(ctx, "POST", c.teamPath("service_users"), user, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetServiceUser returns the named service user.
+func (c *Client) GetServiceUser(ctx context.Context, name string) (*ServiceUser, error) {
+	var user ServiceUser
+	if err := c.do(ctx, "GET", c.teamPath("service_users", name), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateServiceUser sets the status of the service user named user.Name.
+// ASA does not delete service users; they are disabled instead.
+func (c *Client) UpdateServiceUser(ctx context.Context, user ServiceUser) error {
+	return c.do(ctx, "PUT", c.teamPath("service_users", user.Name), user, nil)
+}
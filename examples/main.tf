@@ -47,5 +47,6 @@ resource "oktaasa_assign_group" "dev-group-assignment" {
 }
 
 output "enrollment_token" {
-  value = "${oktaasa_enrollment_token.test-token.token_value}"
+  value     = "${oktaasa_enrollment_token.test-token.token_value}"
+  sensitive = true
 }
@@ -1,8 +1,10 @@
 module github.com/terraform-providers/terraform-provider-oktaasa
 
-go 1.13
+go 1.16
 
 require (
-	github.com/hashicorp/terraform v0.12.20
+	github.com/ProtonMail/go-crypto v0.0.0-20220113124808-70ae35bab23f
+	github.com/hashicorp/terraform-plugin-sdk/v2 v2.10.1
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 	gopkg.in/resty.v1 v1.12.0
 )
@@ -0,0 +1,207 @@
+package oktaasa
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+// attributeOwner is a user or group whose attributes a resource manages.
+// The resource's schema keys are the ASA attribute names. Only the attributes
+// set in the configuration are managed; the others are left alone.
+type attributeOwner struct {
+	kind string
+	name string
+
+	list   func(ctx context.Context) ([]asa.Attribute, error)
+	create func(ctx context.Context, attribute asa.Attribute) error
+	update func(ctx context.Context, attribute asa.Attribute) error
+	remove func(ctx context.Context, id string) error
+}
+
+func userAttributeOwner(client *asa.Client, name string) *attributeOwner {
+	return &attributeOwner{
+		kind: "user",
+		name: name,
+		list: func(ctx context.Context) ([]asa.Attribute, error) {
+			return client.UserAttributes(ctx, name)
+		},
+		create: func(ctx context.Context, attribute asa.Attribute) error {
+			return client.CreateUserAttribute(ctx, name, attribute)
+		},
+		update: func(ctx context.Context, attribute asa.Attribute) error {
+			return client.UpdateUserAttribute(ctx, name, attribute)
+		},
+		remove: func(ctx context.Context, id string) error {
+			return client.DeleteUserAttribute(ctx, name, id)
+		},
+	}
+}
+
+func groupAttributeOwner(client *asa.Client, name string) *attributeOwner {
+	return &attributeOwner{
+		kind: "group",
+		name: name,
+		list: func(ctx context.Context) ([]asa.Attribute, error) {
+			return client.GroupAttributes(ctx, name)
+		},
+		create: func(ctx context.Context, attribute asa.Attribute) error {
+			return client.CreateGroupAttribute(ctx, name, attribute)
+		},
+		update: func(ctx context.Context, attribute asa.Attribute) error {
+			return client.UpdateGroupAttribute(ctx, name, attribute)
+		},
+		remove: func(ctx context.Context, id string) error {
+			return client.DeleteGroupAttribute(ctx, name, id)
+		},
+	}
+}
+
+// byName returns the owner's attributes keyed by name.
+func (o *attributeOwner) byName(ctx context.Context) (map[string]asa.Attribute, error) {
+	list, err := o.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[string]asa.Attribute, len(list))
+	for _, a := range list {
+		attributes[a.Name] = a
+	}
+	return attributes, nil
+}
+
+// managedAttributes returns the names among names that the resource
+// manages, which are the ones set in its configuration or state.
+func managedAttributes(d *schema.ResourceData, names []string) []string {
+	var managed []string
+	for _, name := range names {
+		if _, ok := d.GetOk(name); ok {
+			managed = append(managed, name)
+		}
+	}
+	return managed
+}
+
+// apply creates, updates or removes the attributes among names that changed.
+// An attribute dropped from the configuration is removed.
+func (o *attributeOwner) apply(ctx context.Context, d *schema.ResourceData, names []string) diag.Diagnostics {
+	existing, err := o.byName(ctx)
+	if err != nil {
+		return errorDiag(err, "Error when reading attributes of %s %s", o.kind, o.name)
+	}
+
+	for _, name := range names {
+		if !d.HasChange(name) {
+			continue
+		}
+
+		current, found := existing[name]
+		value, ok := d.GetOk(name)
+
+		switch {
+		case !ok && found:
+			err = o.remove(ctx, current.ID)
+			if err != nil && !asa.IsNotFound(err) {
+				return errorDiag(err, "Error when removing %s of %s %s", name, o.kind, o.name)
+			}
+			continue
+		case !ok:
+			continue
+		case found:
+			err = o.update(ctx, asa.Attribute{ID: current.ID, Name: name, Value: value})
+		default:
+			err = o.create(ctx, asa.Attribute{Name: name, Value: value})
+		}
+
+		if asa.IsConflict(err) {
+			return errorDiag(err, "Error when setting %s of %s %s: %v is already used by another %s", name, o.kind, o.name, value, o.kind)
+		} else if err != nil {
+			return errorDiag(err, "Error when setting %s of %s %s", name, o.kind, o.name)
+		}
+	}
+
+	return nil
+}
+
+// read sets names from the owner's attributes. Missing attributes are set to
+// the zero value so removing one outside of Terraform shows up as drift. It
+// returns false if the owner does not exist.
+func (o *attributeOwner) read(ctx context.Context, d *schema.ResourceData, names []string) (bool, diag.Diagnostics) {
+	existing, err := o.byName(ctx)
+	if asa.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errorDiag(err, "Error when reading attributes of %s %s", o.kind, o.name)
+	}
+
+	for _, name := range names {
+		attribute, found := existing[name]
+
+		switch d.Get(name).(type) {
+		case int:
+			value := 0
+			if found {
+				if value, err = strconv.Atoi(attribute.String()); err != nil {
+					return false, errorDiag(err, "Error when reading %s of %s %s", name, o.kind, o.name)
+				}
+			}
+			d.Set(name, value)
+		default:
+			value := ""
+			if found {
+				value = attribute.String()
+			}
+			d.Set(name, value)
+		}
+	}
+
+	return true, nil
+}
+
+// importAll sets every attribute among names that the owner has, so the
+// imported resource manages them.
+func (o *attributeOwner) importAll(ctx context.Context, d *schema.ResourceData, names []string) error {
+	existing, err := o.byName(ctx)
+	if err != nil {
+		return fmt.Errorf("reading attributes of %s %s: %w", o.kind, o.name, err)
+	}
+
+	var set []string
+	for _, name := range names {
+		if _, found := existing[name]; found {
+			set = append(set, name)
+		}
+	}
+
+	if _, diags := o.read(ctx, d, set); diags.HasError() {
+		return fmt.Errorf("reading attributes of %s %s", o.kind, o.name)
+	}
+	return nil
+}
+
+// removeAll deletes the attributes among names that exist.
+func (o *attributeOwner) removeAll(ctx context.Context, names []string) diag.Diagnostics {
+	existing, err := o.byName(ctx)
+	if asa.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading attributes of %s %s", o.kind, o.name)
+	}
+
+	for _, name := range names {
+		attribute, found := existing[name]
+		if !found {
+			continue
+		}
+		if err := o.remove(ctx, attribute.ID); err != nil && !asa.IsNotFound(err) {
+			return errorDiag(err, "Error when removing %s of %s %s", name, o.kind, o.name)
+		}
+	}
+
+	return nil
+}
@@ -1,93 +1,102 @@
 package oktaasa
 
 import (
-	"bytes"
-	"encoding/json"
-	"gopkg.in/resty.v1"
+	"context"
+	"fmt"
 	"log"
-)
-
-func checkSoftDelete(response []byte) (bool, error) {
-	type sftResp struct {
-		Name      string `json:"name"`
-		DeletedAt string `json:"deleted_at"`
-		RemovedAt string `json:"removed_at"`
-	}
+	"strings"
+	"time"
 
-	var resp sftResp
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
-	err := json.Unmarshal(response, &resp)
+// errorDiag reports err as an error diagnostic. The summary says what failed;
+// the detail carries the API error with its status, code and request ID.
+func errorDiag(err error, summary string, args ...interface{}) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf(summary, args...),
+		Detail:   err.Error(),
+	}}
+}
 
+// validateDuration checks that a string attribute is a positive duration
+// such as "720h".
+func validateDuration(v interface{}, k string) (ws []string, errs []error) {
+	d, err := time.ParseDuration(v.(string))
 	if err != nil {
-		return false, err
+		errs = append(errs, fmt.Errorf("%q must be a duration such as \"720h\": %s", k, err))
+	} else if d <= 0 {
+		errs = append(errs, fmt.Errorf("%q must be positive, got %s", k, d))
 	}
+	return
+}
 
-	if len(resp.DeletedAt) > 0 || len(resp.RemovedAt) > 0 {
-		log.Printf("[DEBUG] Object %s was deleted", resp.Name)
-		return true, err
-	} else {
-		return false, err
+// expandStringSet converts a set of strings into a slice, which is empty
+// rather than nil for an empty set so it is sent as [] rather than null.
+func expandStringSet(set *schema.Set) []string {
+	list := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		list = append(list, v.(string))
 	}
+	return list
 }
 
-func SendGet(bearer string, path string) (*resty.Response, error) {
-	composedUrl := url + path
-	resp, err := resty.R().
-		SetHeaders(map[string]string{
-			"Accept":       "application/json",
-			"Content-Type": "Application/json"}).
-		SetAuthToken(bearer).
-		Get(composedUrl)
-
-	log.Printf("[DEBUG] Get to %s. Status code: %d", composedUrl, resp.StatusCode())
-	return resp, err
-
+// flattenStringSet returns a set of the strings in list.
+func flattenStringSet(list []string) *schema.Set {
+	set := schema.NewSet(schema.HashString, nil)
+	for _, v := range list {
+		set.Add(v)
+	}
+	return set
 }
 
-func SendPost(BearerToken, path string, body []byte) (*resty.Response, error) {
-	composedUrl := url + path
-
-	resp, err := resty.R().
-		SetHeaders(map[string]string{
-			"Accept":       "application/json",
-			"Content-Type": "Application/json"}).
-		SetAuthToken(BearerToken).
-		SetBody(bytes.NewBuffer(body)).
-		Post(composedUrl)
-
-	log.Printf("[DEBUG] POST to %s. Status code: %d", composedUrl, resp.StatusCode())
-	return resp, err
+// rotateAfterCustomizeDiff replaces a resource whose issued_at attribute is
+// older than its rotate_after duration.
+func rotateAfterCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	rotateAfter := d.Get("rotate_after").(string)
+	issuedAt := d.Get("issued_at").(string)
 
-}
+	if d.Id() == "" || rotateAfter == "" || issuedAt == "" {
+		return nil
+	}
 
-func SendPut(BearerToken, path string, body []byte) (*resty.Response, error) {
-	composedUrl := url + path
+	maxAge, err := time.ParseDuration(rotateAfter)
+	if err != nil {
+		return err
+	}
+	issued, err := time.Parse(time.RFC3339, issuedAt)
+	if err != nil {
+		return fmt.Errorf("parsing issued_at of %s: %w", d.Id(), err)
+	}
 
-	resp, err := resty.R().
-		SetHeaders(map[string]string{
-			"Accept":       "application/json",
-			"Content-Type": "Application/json"}).
-		SetAuthToken(BearerToken).
-		SetBody(bytes.NewBuffer(body)).
-		Put(composedUrl)
+	if time.Since(issued) < maxAge {
+		return nil
+	}
 
-	log.Printf("[DEBUG] PUT to %s. Status code: %d", composedUrl, resp.StatusCode())
-	return resp, err
+	log.Printf("[INFO] %s was issued at %s and is due for rotation", d.Id(), issuedAt)
 
+	if err := d.SetNewComputed("issued_at"); err != nil {
+		return err
+	}
+	return d.ForceNew("issued_at")
 }
 
-func SendDelete(BearerToken, path string, body []byte) (*resty.Response, error) {
-	composedUrl := url + path
-
-	resp, err := resty.R().
-		SetHeaders(map[string]string{
-			"Accept":       "application/json",
-			"Content-Type": "Application/json"}).
-		SetAuthToken(BearerToken).
-		SetBody(bytes.NewBuffer(body)).
-		Delete(composedUrl)
+// splitImportID splits a composite import ID such as "project/group" into its
+// parts. format names the parts and is used in the error message.
+func splitImportID(id, format string) ([]string, error) {
+	n := strings.Count(format, "/") + 1
+	parts := strings.SplitN(id, "/", n)
 
-	log.Printf("[DEBUG] DELETE to %s. Status code: %d", composedUrl, resp.StatusCode())
-	return resp, err
+	if len(parts) != n {
+		return nil, fmt.Errorf("unexpected format of ID %q, expected %s", id, format)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("unexpected format of ID %q, expected %s", id, format)
+		}
+	}
 
+	return parts, nil
 }
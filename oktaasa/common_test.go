@@ -0,0 +1,80 @@
+package oktaasa
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSplitImportID(t *testing.T) {
+	cases := []struct {
+		id      string
+		want    []string
+		wantErr bool
+	}{
+		{id: "my-project/my-group", want: []string{"my-project", "my-group"}},
+		{id: "my-project/", wantErr: true},
+		{id: "/my-group", wantErr: true},
+		{id: "my-group", wantErr: true},
+	}
+
+	for _, c := range cases {
+		got, err := splitImportID(c.id, "project/group")
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %v", c.id, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.id, err)
+		} else if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: expected %v, got %v", c.id, c.want, got)
+		}
+	}
+}
+
+func TestRotateAfterCustomizeDiff(t *testing.T) {
+	cases := []struct {
+		name        string
+		rotateAfter string
+		age         time.Duration
+		wantNew     bool
+	}{
+		{name: "expired", rotateAfter: "1h", age: 2 * time.Hour, wantNew: true},
+		{name: "fresh", rotateAfter: "1h", age: 10 * time.Minute},
+		{name: "no rotate_after", age: 2 * time.Hour},
+	}
+
+	for _, c := range cases {
+		state := &terraform.InstanceState{
+			ID: "token-id",
+			Attributes: map[string]string{
+				"id":           "token-id",
+				"project_name": "my-project",
+				"description":  "my token",
+				"rotate_after": c.rotateAfter,
+				"issued_at":    time.Now().Add(-c.age).UTC().Format(time.RFC3339),
+				"token_value":  "secret",
+			},
+		}
+		config := map[string]interface{}{
+			"project_name": "my-project",
+			"description":  "my token",
+		}
+		if c.rotateAfter != "" {
+			config["rotate_after"] = c.rotateAfter
+		}
+
+		diff, err := resourceOKTAASAToken().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if got := diff != nil && diff.RequiresNew(); got != c.wantNew {
+			t.Errorf("%s: expected replacement %t, got %t", c.name, c.wantNew, got)
+		}
+	}
+}
@@ -1,58 +1,41 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
-	"gopkg.in/resty.v1"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 type Config struct {
 	key    string
 	secret string
 	team   string
-}
-
-const url string = "https://app.scaleft.com/v1"
+	apiURL string
 
-var teamName = ""
+	maxRetries int
+	maxWait    time.Duration
 
-type Bearer struct {
-	TeamName    string `json:"-"`
-	BearerToken string `json:"bearer_token"`
+	maxRequestsPerSecond  int
+	maxConcurrentRequests int
 }
 
-// returns interface that contains Bearer token.
-func (c *Config) Authorization() (interface{}, error) {
-
-	// get bearer token
-	resp := GetToken(c.team, c.key, c.secret)
+// returns an ASA API client for the configured team.
+func (c *Config) Authorization(ctx context.Context) (*asa.Client, error) {
+	client := asa.NewClient(c.team, c.key, c.secret,
+		asa.WithBaseURL(strings.TrimSuffix(c.apiURL, "/")),
+		asa.WithRetry(c.maxRetries, c.maxWait),
+		asa.WithRateLimit(c.maxRequestsPerSecond, c.maxConcurrentRequests))
 
-	teamName = c.team
-
-	token := Bearer{}
-	jsonErr := json.Unmarshal(resp, &token)
-	if jsonErr != nil {
-		log.Printf("[DEBUG] Error getting bearer token:%s", jsonErr)
+	// get bearer token, so that bad credentials fail before any resource is touched.
+	if err := client.Authenticate(ctx); err != nil {
+		return nil, fmt.Errorf("Getting a bearer token for team %s failed. Check the ASA API key and secret: %w", c.team, err)
 	}
 
-	return token, nil
-}
-
-func GetToken(team, key, secret string) []byte {
-	log.Printf("[DEBUG] Getting bearer token from Config.")
-
-	composedUrl := url + "/teams/" + team + "/service_token"
-
-	credentials := map[string]interface{}{"key_id": key, "key_secret": secret}
-
-	resp, _ := resty.R().
-		SetBody(credentials).
-		SetHeaders(map[string]string{
-			"Accept":       "application/json",
-			"Content-Type": "Application/json"}).
-		Post(composedUrl)
-
-	return resp.Body()
+	log.Printf("[DEBUG] Authenticated to team %s", c.team)
 
+	return client, nil
 }
@@ -0,0 +1,81 @@
+package oktaasa
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// encryptValue encrypts value for the PGP public key in pgpKey, which is
+// either base64-encoded or ASCII-armored. It returns the fingerprint of the
+// key and the base64-encoded encrypted message, which can be decrypted with
+// `base64 -d | gpg -d`.
+func encryptValue(pgpKey, value string) (fingerprint, encrypted string, err error) {
+	entity, err := readPGPKey(pgpKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("encrypting with PGP key: %w", err)
+	}
+	if _, err := w.Write([]byte(value)); err != nil {
+		return "", "", fmt.Errorf("encrypting with PGP key: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("encrypting with PGP key: %w", err)
+	}
+
+	fingerprint = hex.EncodeToString(entity.PrimaryKey.Fingerprint[:])
+	return fingerprint, base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// validatePGPKey checks that a string attribute holds a PGP public key that
+// can encrypt.
+func validatePGPKey(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := readPGPKey(v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
+
+// readPGPKey parses the first public key in pgpKey, which must have a valid
+// encryption key. Sign-only, expired and revoked keys are rejected.
+func readPGPKey(pgpKey string) (*openpgp.Entity, error) {
+	pgpKey = strings.TrimSpace(pgpKey)
+
+	var entities openpgp.EntityList
+	var err error
+
+	switch {
+	case strings.HasPrefix(pgpKey, "keybase:"):
+		return nil, fmt.Errorf("keybase PGP keys are not supported, use a base64-encoded or ASCII-armored public key")
+	case strings.HasPrefix(pgpKey, "-----BEGIN PGP"):
+		entities, err = openpgp.ReadArmoredKeyRing(strings.NewReader(pgpKey))
+	default:
+		var raw []byte
+		if raw, err = base64.StdEncoding.DecodeString(pgpKey); err != nil {
+			return nil, fmt.Errorf("decoding PGP key: %w", err)
+		}
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(raw))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading PGP key: %w", err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("reading PGP key: no key found")
+	}
+	if _, ok := entities[0].EncryptionKey(time.Now()); !ok {
+		return nil, fmt.Errorf("reading PGP key: no valid encryption key found, the key may be sign-only, expired or revoked")
+	}
+
+	return entities[0], nil
+}
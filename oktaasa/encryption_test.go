@@ -0,0 +1,98 @@
+package oktaasa
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+func TestEncryptValue(t *testing.T) {
+	entity, pgpKey := testPGPKey(t)
+
+	var armored bytes.Buffer
+	aw, _ := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	entity.Serialize(aw)
+	aw.Close()
+
+	for name, pgpKey := range map[string]string{
+		"base64":  pgpKey,
+		"armored": armored.String(),
+	} {
+		fingerprint, encrypted, err := encryptValue(pgpKey, "secret value")
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if fingerprint != hex.EncodeToString(entity.PrimaryKey.Fingerprint[:]) {
+			t.Errorf("%s: unexpected fingerprint %s", name, fingerprint)
+		}
+
+		ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+		if err != nil {
+			t.Fatalf("%s: decoding: %s", name, err)
+		}
+		md, err := openpgp.ReadMessage(bytes.NewReader(ciphertext), openpgp.EntityList{entity}, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: decrypting: %s", name, err)
+		}
+		plaintext, _ := ioutil.ReadAll(md.UnverifiedBody)
+		if string(plaintext) != "secret value" {
+			t.Errorf("%s: unexpected plaintext %q", name, plaintext)
+		}
+	}
+
+	if _, _, err := encryptValue("keybase:someone", "secret value"); err == nil {
+		t.Error("expected an error for a keybase key")
+	}
+}
+
+// testPGPKey generates a PGP key and returns it with its base64-encoded
+// public key.
+func testPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+
+	var raw bytes.Buffer
+	if err := entity.Serialize(&raw); err != nil {
+		t.Fatalf("serializing key: %s", err)
+	}
+
+	return entity, base64.StdEncoding.EncodeToString(raw.Bytes())
+}
+
+func TestValidatePGPKey(t *testing.T) {
+	_, pgpKey := testPGPKey(t)
+
+	if _, errs := validatePGPKey(pgpKey, "pgp_key"); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	signOnly := testPGPSignOnlyKey(t)
+
+	if _, errs := validatePGPKey(signOnly, "pgp_key"); len(errs) == 0 {
+		t.Error("expected an error for a sign-only key")
+	}
+	if _, _, err := encryptValue(signOnly, "secret value"); err == nil {
+		t.Error("expected an error encrypting with a sign-only key")
+	}
+}
+
+// testPGPSignOnlyKey generates a PGP key without an encryption subkey and
+// returns its base64-encoded public key.
+func testPGPSignOnlyKey(t *testing.T) string {
+	entity, _ := testPGPKey(t)
+	entity.Subkeys = nil
+
+	var raw bytes.Buffer
+	if err := entity.Serialize(&raw); err != nil {
+		t.Fatalf("serializing key: %s", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(raw.Bytes())
+}
@@ -1,11 +1,16 @@
 package oktaasa
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
-func Provider() terraform.ResourceProvider {
+func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"oktaasa_key": &schema.Schema{
@@ -28,25 +33,85 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("OKTAASA_TEAM", nil),
 				Description: "OKTAASA Team.",
 			},
+
+			"oktaasa_api_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OKTAASA_API_URL", asa.DefaultBaseURL),
+				Description: "OKTAASA API base URL.",
+			},
+
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      asa.DefaultMaxRetries,
+				ValidateFunc: validation.IntBetween(0, 100),
+				Description:  "Maximum number of retries for throttled (429) and failing (502, 503, 504) API requests.",
+			},
+
+			"max_wait_seconds": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(asa.DefaultMaxWait / time.Second),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of seconds to wait before a single retry.",
+			},
+
+			"max_requests_per_second": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of API requests per second. 0 means no limit.",
+			},
+
+			"max_concurrent_requests": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of API requests in flight at once. 0 means no limit.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"oktaasa_project":          resourceOKTAASAProject(),
 			"oktaasa_enrollment_token": resourceOKTAASAToken(),
 			"oktaasa_assign_group":     resourceOKTAASAAssignGroup(),
 			"oktaasa_create_group":     resourceOKTAASACreateGroup(),
+			"oktaasa_service_user":     resourceOKTAASAServiceUser(),
+			"oktaasa_service_user_key": resourceOKTAASAServiceUserKey(),
+			"oktaasa_user_attributes":  resourceOKTAASAUserAttributes(),
+			"oktaasa_group_attributes": resourceOKTAASAGroupAttributes(),
+			"oktaasa_group_member":     resourceOKTAASAGroupMember(),
+			"oktaasa_group_membership": resourceOKTAASAGroupMembership(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	config := Config{
 		key:    d.Get("oktaasa_key").(string),
 		secret: d.Get("oktaasa_secret").(string),
 		team:   d.Get("oktaasa_team").(string),
+		apiURL: d.Get("oktaasa_api_url").(string),
+
+		maxRetries: d.Get("max_retries").(int),
+		maxWait:    time.Duration(d.Get("max_wait_seconds").(int)) * time.Second,
+
+		maxRequestsPerSecond:  d.Get("max_requests_per_second").(int),
+		maxConcurrentRequests: d.Get("max_concurrent_requests").(int),
 	}
 
-	return config.Authorization()
+	client, err := config.Authorization(ctx)
+	if err != nil {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Unable to authenticate to Okta's ASA",
+			Detail:   err.Error(),
+		}}
+	}
 
+	return client, nil
 }
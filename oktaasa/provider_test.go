@@ -1,31 +1,56 @@
 package oktaasa
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa/asatest"
 )
 
-var testAccProviders map[string]terraform.ResourceProvider
+var testAccProviderFactories map[string]func() (*schema.Provider, error)
 var testAccProvider *schema.Provider
 
+// testAccServer is the fake ASA API that acceptance tests run against when
+// OKTAASA_KEY is not set.
+var testAccServer *asatest.Server
+
 func init() {
-	testAccProvider = Provider().(*schema.Provider)
-	testAccProviders = map[string]terraform.ResourceProvider{
-		"oktaasa": testAccProvider,
+	testAccProvider = Provider()
+	testAccProviderFactories = map[string]func() (*schema.Provider, error){
+		"oktaasa": func() (*schema.Provider, error) { return testAccProvider, nil },
+	}
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv("OKTAASA_KEY") == "" {
+		testAccServer = asatest.NewServer("test-acc-team", "test-acc-key", "test-acc-secret")
+
+		os.Setenv("OKTAASA_API_URL", testAccServer.BaseURL())
+		os.Setenv("OKTAASA_KEY", testAccServer.KeyID)
+		os.Setenv("OKTAASA_KEY_SECRET", testAccServer.KeySecret)
+		os.Setenv("OKTAASA_TEAM", testAccServer.Team)
+	}
+
+	code := m.Run()
+
+	if testAccServer != nil {
+		testAccServer.Close()
 	}
+	os.Exit(code)
 }
 
 func TestProvider(t *testing.T) {
-	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
 
 func TestProvider_impl(t *testing.T) {
-	var _ terraform.ResourceProvider = Provider()
+	var _ *schema.Provider = Provider()
 }
 
 func testAccPreCheck(t *testing.T) {
@@ -39,3 +64,16 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("OKTAASA_TEAM must be set for acceptance tests")
 	}
 }
+
+// testAccProjectScopedImportID returns the project/id import ID of a resource
+// that belongs to a project.
+func testAccProjectScopedImportID(rn string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return "", fmt.Errorf("resource not found: %s", rn)
+		}
+
+		return rs.Primary.Attributes["project_name"] + "/" + rs.Primary.ID, nil
+	}
+}
@@ -1,27 +1,45 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/hashicorp/terraform/helper/schema"
 	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func resourceOKTAASAAssignGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOKTAASAAssignGroupCreate,
-		Read:   resourceOKTAASAAssignGroupRead,
-		Update: resourceOKTAASAAssignGroupUpdate,
-		Delete: resourceOKTAASAAssignGroupDelete,
+		CreateContext: resourceOKTAASAAssignGroupCreate,
+		ReadContext:   resourceOKTAASAAssignGroupRead,
+		UpdateContext: resourceOKTAASAAssignGroupUpdate,
+		DeleteContext: resourceOKTAASAAssignGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOKTAASAAssignGroupImport,
+		},
+
+		// Version 0 used the bare group name as the ID.
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceOKTAASAAssignGroupV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceOKTAASAAssignGroupStateUpgradeV0,
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
 			"project_name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"group_name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"server_access": {
 				Type:     schema.TypeBool,
@@ -42,128 +60,172 @@ func resourceOKTAASAAssignGroup() *schema.Resource {
 	}
 }
 
-func resourceOKTAASAAssignGroupCreate(d *schema.ResourceData, m interface{}) error {
+// resourceOKTAASAAssignGroupV0 is the schema of version 0 of the resource.
+func resourceOKTAASAAssignGroupV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"project_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"group_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"server_access": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"server_admin": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"create_server_group": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
 
-	token := m.(Bearer)
+func resourceOKTAASAAssignGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
 	//get settings from terraform config.
 	project_name := d.Get("project_name").(string)
 	oktaGroupName := d.Get("group_name").(string)
-	serverAccess := d.Get("server_access")
-	serverAdmin := d.Get("server_admin")
-	groupSync := d.Get("create_server_group")
 
 	log.Printf("[DEBUG] Assigning group %s to the project: %s", oktaGroupName, project_name)
 
 	// set POST parameters for group assignment
-	oktaGroupSettings := map[string]interface{}{
-		"group":               oktaGroupName,
-		"server_access":       serverAccess,
-		"server_admin":        serverAdmin,
-		"create_server_group": groupSync}
-
-	oktaGroupDescriptionB, _ := json.Marshal(oktaGroupSettings)
+	oktaGroupSettings := asa.ProjectGroup{
+		Group:             oktaGroupName,
+		ServerAccess:      d.Get("server_access").(bool),
+		ServerAdmin:       d.Get("server_admin").(bool),
+		CreateServerGroup: d.Get("create_server_group").(bool),
+	}
 
 	//make API call to assign Okta group to a project
-	resp, err := SendPost(token.BearerToken, "/teams/"+teamName+"/projects/"+project_name+"/groups", oktaGroupDescriptionB)
+	err := client.CreateProjectGroup(ctx, project_name, oktaGroupSettings)
 
 	if err != nil {
-		return fmt.Errorf("[ERROR] Error when assigning group: %s. Error: %s", oktaGroupName, err)
+		return errorDiag(err, "Error when assigning group %s to project %s", oktaGroupName, project_name)
 	}
 
-	statusCode := resp.StatusCode()
-	if statusCode < 300 {
-		log.Printf("[DEBUG] Success. Group %s was assigned to %s", oktaGroupName, project_name)
-	} else {
-		return fmt.Errorf("[ERROR] Error happened while assigning group %s to a project: %s", oktaGroupName, resp)
-	}
+	log.Printf("[DEBUG] Success. Group %s was assigned to %s", oktaGroupName, project_name)
 
-	d.SetId(oktaGroupName)
+	d.SetId(project_name + "/" + oktaGroupName)
 
-	return resourceOKTAASAAssignGroupRead(d, m)
+	return resourceOKTAASAAssignGroupRead(ctx, d, m)
 }
 
-type Group struct {
-	Name         string `json:"group"`
-	ServerAccess bool   `json:"server_access"`
-	ServerAdmin  bool   `json:"server_admin"`
-	GroupSync    bool   `json:"create_server_group"`
-}
+func resourceOKTAASAAssignGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
-func resourceOKTAASAAssignGroupRead(d *schema.ResourceData, m interface{}) error {
-	sessionToken := m.(Bearer)
-	groupName := d.Id()
-
-	log.Printf("[INFO] Group ID is: %s", groupName)
-	//get project_name from terraform config.
-	projectName := d.Get("project_name").(string)
-
-	resp, err := SendGet(sessionToken.BearerToken, "/teams/"+teamName+"/projects/"+projectName+"/groups/"+groupName)
+	log.Printf("[INFO] Group assignment ID is: %s", d.Id())
 
+	parts, err := splitImportID(d.Id(), "project/group")
 	if err != nil {
-		return fmt.Errorf("[ERROR] Error when reading group state: %s. Error: %s", groupName, err)
+		return diag.FromErr(err)
 	}
+	projectName, groupName := parts[0], parts[1]
 
-	status := resp.StatusCode()
+	group, err := client.GetProjectGroup(ctx, projectName, groupName)
 
-	// API can return 200, but also have deleted_at or removed_at value.
-	deleted, err := checkSoftDelete(resp.Body())
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when attempting to check for soft delete, while reading group state: %s. Error: %s", groupName, err)
+	if asa.IsNotFound(err) {
+		log.Printf("[INFO] group %s is no assigned to the project. %s", groupName, projectName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading assignment of group %s to project %s", groupName, projectName)
 	}
 
-	if status == 200 && deleted == true {
+	// API can return 200, but also have deleted_at or removed_at value.
+	if group.Deleted() {
 		log.Printf("[INFO] Group %s was removed from project %s", groupName, projectName)
 		d.SetId("")
 		return nil
-	} else if status == 200 && deleted == false {
-		log.Printf("[INFO] Group %s is assigned to project %s ", groupName, projectName)
+	}
 
-		var group Group
+	log.Printf("[INFO] Group %s is assigned to project %s ", groupName, projectName)
 
-		err := json.Unmarshal(resp.Body(), &group)
-		if err != nil {
-			return fmt.Errorf("Unable to unmarshal group settings")
-		}
+	d.Set("project_name", projectName)
+	d.Set("group_name", group.Group)
+	d.Set("server_access", group.ServerAccess)
+	d.Set("server_admin", group.ServerAdmin)
+	d.Set("create_server_group", group.CreateServerGroup)
 
-		d.SetId(group.Name)
-		d.Set("server_access", group.ServerAccess)
-		d.Set("server_admin", group.ServerAdmin)
-		d.Set("create_server_group", group.GroupSync)
+	return nil
+}
 
-		return nil
-	} else if status == 404 {
-		log.Printf("[INFO] group %s is no assigned to the project. %s", groupName, projectName)
-		d.SetId("")
-		return nil
-	} else {
-		return fmt.Errorf("[DEBUG] failed to read group assignment state. Project: %s Group: %s Status code: %d", projectName, groupName, status)
+// resourceOKTAASAAssignGroupImport imports a group assignment from an ID of
+// the form project/group, which is also the resource ID.
+func resourceOKTAASAAssignGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := splitImportID(d.Id(), "project/group"); err != nil {
+		return nil, err
 	}
+
+	return []*schema.ResourceData{d}, nil
 }
 
-func resourceOKTAASAAssignGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	return resourceOKTAASAAssignGroupCreate(d, m)
+// resourceOKTAASAAssignGroupStateUpgradeV0 rewrites the version 0 ID, the
+// group name, into the project/group form.
+func resourceOKTAASAAssignGroupStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	projectName, _ := rawState["project_name"].(string)
+	groupName, _ := rawState["id"].(string)
+
+	if projectName == "" || groupName == "" {
+		return nil, fmt.Errorf("upgrading group assignment state: project_name and id must be set, got %q and %q", projectName, groupName)
+	}
+
+	rawState["id"] = projectName + "/" + groupName
+
+	return rawState, nil
 }
 
-func resourceOKTAASAAssignGroupDelete(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASAAssignGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
-	//get project_name from terraform config.
+	// project_name and group_name force a new assignment, so only the
+	// permissions can change here.
 	projectName := d.Get("project_name").(string)
-	groupName := d.Id()
+	groupName := d.Get("group_name").(string)
 
-	resp, err := SendDelete(token.BearerToken, "/teams/"+teamName+"/projects/"+projectName+"/groups/"+groupName, make([]byte, 0))
+	log.Printf("[DEBUG] Updating assignment of group %s to the project: %s", groupName, projectName)
+
+	oktaGroupSettings := asa.ProjectGroup{
+		Group:             groupName,
+		ServerAccess:      d.Get("server_access").(bool),
+		ServerAdmin:       d.Get("server_admin").(bool),
+		CreateServerGroup: d.Get("create_server_group").(bool),
+	}
+
+	err := client.UpdateProjectGroup(ctx, projectName, oktaGroupSettings)
 
 	if err != nil {
-		return fmt.Errorf("[ERROR] Error when deleting group: %s. Error: %s", groupName, err)
+		return errorDiag(err, "Error when updating assignment of group %s to project %s", groupName, projectName)
 	}
 
-	status := resp.StatusCode()
+	log.Printf("[DEBUG] Success. Assignment of group %s to %s was updated", groupName, projectName)
+
+	return resourceOKTAASAAssignGroupRead(ctx, d, m)
+}
+
+func resourceOKTAASAAssignGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	projectName := d.Get("project_name").(string)
+	groupName := d.Get("group_name").(string)
+
+	err := client.DeleteProjectGroup(ctx, projectName, groupName)
 
-	if status < 300 || status == 404 {
+	if err == nil || asa.IsNotFound(err) {
 		log.Printf("[INFO] Group %s was successfully deleted", projectName)
 	} else {
-		return fmt.Errorf("[ERROR] Something went wrong while deleting group %s. Error: %s", d.Id(), resp)
+		return errorDiag(err, "Error when removing group %s from project %s", groupName, projectName)
 	}
 
 	return nil
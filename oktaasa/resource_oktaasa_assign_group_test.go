@@ -1,29 +1,28 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
-var projectName string
-
 func TestAccGroupAssign(t *testing.T) {
-	groupassign := &Group{}
+	groupassign := &asa.ProjectGroup{}
 
-	project := &Project{}
+	project := &asa.Project{}
 	projectName := "test-acc-project_g"
 
-	//group := &Group{}
+	//group := &asa.Group{}
 	groupName := "test-acc-group_g"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccGroupAssignCheckDestroy(groupassign),
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGroupAssignCheckDestroy(groupassign),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccProjectGroupCreateConfig3,
@@ -58,10 +57,18 @@ func TestAccGroupAssign(t *testing.T) {
 					),
 				),
 			},
+			{
+				ResourceName:      "oktaasa_assign_group.test-acc-group-assignment",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 			{
 				Config: testAccGroupAssignUpdateConfig,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					testAccGroupAssignCheckExists("oktaasa_assign_group.test-acc-group-assignment", groupassign),
+					resource.TestCheckResourceAttr(
+						"oktaasa_assign_group.test-acc-group-assignment", "id", projectName+"/"+groupName,
+					),
 					resource.TestCheckResourceAttr(
 						"oktaasa_assign_group.test-acc-group-assignment", "project_name", projectName,
 					),
@@ -83,7 +90,23 @@ func TestAccGroupAssign(t *testing.T) {
 	})
 }
 
-func testAccProjectCheckExists3(rn string, p *Project) resource.TestCheckFunc {
+func TestResourceOKTAASAAssignGroupStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"id":           "test-acc-group_g",
+		"project_name": "test-acc-project_g",
+		"group_name":   "test-acc-group_g",
+	}
+
+	v1, err := resourceOKTAASAAssignGroupStateUpgradeV0(context.Background(), v0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v1["id"] != "test-acc-project_g/test-acc-group_g" {
+		t.Fatalf("unexpected ID: %v", v1["id"])
+	}
+}
+
+func testAccProjectCheckExists3(rn string, p *asa.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		if !ok {
@@ -95,23 +118,20 @@ func testAccProjectCheckExists3(rn string, p *Project) resource.TestCheckFunc {
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+rs.Primary.ID)
+		found, err := client.GetProject(context.Background(), rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccGroupCheckExists2(rn string, p *Group) resource.TestCheckFunc {
+func testAccGroupCheckExists2(rn string, p *asa.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		if !ok {
@@ -123,68 +143,63 @@ func testAccGroupCheckExists2(rn string, p *Group) resource.TestCheckFunc {
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/groups/"+rs.Primary.ID)
+		found, err := client.GetGroup(context.Background(), rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccGroupAssignCheckExists(rn string, p *Group) resource.TestCheckFunc {
+func testAccGroupAssignCheckExists(rn string, p *asa.ProjectGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		if !ok {
 			return fmt.Errorf("resource not found: %s", rn)
 		}
 
-		// resource ID
+		// resource ID is project/group
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+projectName+"/groups/"+rs.Primary.ID)
+		found, err := client.GetProjectGroup(context.Background(), rs.Primary.Attributes["project_name"], rs.Primary.Attributes["group_name"])
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccGroupAssignCheckDestroy(p *Group) resource.TestCheckFunc {
+func testAccGroupAssignCheckDestroy(p *asa.ProjectGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		config := testAccProvider.Meta().(Bearer)
-
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+projectName+"/groups/"+p.Name)
-		if err != nil {
-			return fmt.Errorf("error getting data source: %s", err)
-		}
-
-		status := resp.StatusCode()
-		deleted, err := checkSoftDelete(resp.Body())
-		if err != nil {
-			return fmt.Errorf("error while checking deleted status: %s", err)
-		}
-
-		if status == 200 && !deleted {
-			//COME BACK
-			return fmt.Errorf("project still exists")
+		client := testAccProvider.Meta().(*asa.Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "oktaasa_assign_group" {
+				continue
+			}
+
+			found, err := client.GetProjectGroup(context.Background(), rs.Primary.Attributes["project_name"], rs.Primary.Attributes["group_name"])
+			if asa.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("error getting data source: %s", err)
+			}
+
+			if !found.Deleted() {
+				return fmt.Errorf("group assignment still exists")
+			}
 		}
 
 		return nil
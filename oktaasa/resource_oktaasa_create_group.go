@@ -1,132 +1,130 @@
 package oktaasa
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/hashicorp/terraform/helper/schema"
+	"context"
 	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func resourceOKTAASACreateGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOKTAASACreateGroupCreate,
-		Read:   resourceOKTAASACreateGroupRead,
-		Update: resourceOKTAASACreateGroupUpdate,
-		Delete: resourceOKTAASACreateGroupDelete,
+		CreateContext: resourceOKTAASACreateGroupCreate,
+		ReadContext:   resourceOKTAASACreateGroupRead,
+		UpdateContext: resourceOKTAASACreateGroupUpdate,
+		DeleteContext: resourceOKTAASACreateGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
+			},
+			"roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{
+						asa.RoleAccessUser,
+						asa.RoleAccessAdmin,
+						asa.RoleReportingUser,
+					}, false),
+				},
 			},
 		},
 	}
 }
 
-func resourceOKTAASACreateGroupCreate(d *schema.ResourceData, m interface{}) error {
-
-	token := m.(Bearer)
+func resourceOKTAASACreateGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
 	//get settings from terraform config.
 	oktaasaGroupName := d.Get("name").(string)
 
 	log.Printf("[DEBUG] Creating group %s", oktaasaGroupName)
 
-	type RolesOptions struct {
-		Name  string   `json:"name"`
-		Roles []string `json:"roles"`
-	}
-
-	options := &RolesOptions{Name: oktaasaGroupName, Roles: []string{}}
+	group := asa.Group{Name: oktaasaGroupName, Roles: expandStringSet(d.Get("roles").(*schema.Set))}
 
-	GroupDescriptionB, _ := json.Marshal(options)
+	//make API call to create the group
+	err := client.CreateGroup(ctx, group)
 
-	//make API call to assign Okta group to a project
-	resp, err := SendPost(token.BearerToken, "/teams/"+teamName+"/groups", GroupDescriptionB)
-
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when creating group: %s. Error: %s", oktaasaGroupName, err)
+	if asa.IsConflict(err) {
+		return errorDiag(err, "Group %s already exists, import it to manage it with Terraform", oktaasaGroupName)
+	} else if err != nil {
+		return errorDiag(err, "Error when creating group %s", oktaasaGroupName)
 	}
 
-	statusCode := resp.StatusCode()
-
-	if statusCode < 300 {
-		log.Printf("[DEBUG] Success. Group %s was created", oktaasaGroupName)
-	} else if statusCode == 409 {
-		log.Printf("[INFO] Group already exists")
-	} else {
-		return fmt.Errorf("[ERROR] Error happened while creating group %s. Error: %s", oktaasaGroupName, resp)
-	}
+	log.Printf("[DEBUG] Success. Group %s was created", oktaasaGroupName)
 
 	d.SetId(oktaasaGroupName)
 
-	return resourceOKTAASACreateGroupRead(d, m)
-}
-
-type SftGroup struct {
-	Name      string   `json:"name"`
-	Roles     []string `json:"roles"`
-	DeletedAt string   `json:"deleted_at"`
+	return resourceOKTAASACreateGroupRead(ctx, d, m)
 }
 
-func resourceOKTAASACreateGroupRead(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASACreateGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 	groupName := d.Id()
 
-	log.Printf("[DEBUG Running Create Group Read function.")
+	log.Printf("[DEBUG] Running Create Group Read function.")
 	log.Printf("[INFO] Group name is: %s", groupName)
 
-	resp, err := SendGet(token.BearerToken, "/teams/"+teamName+"/groups/"+groupName)
+	group, err := client.GetGroup(ctx, groupName)
 
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when reading group state: %s. Error: %s", groupName, err)
-	}
-
-	status := resp.StatusCode()
-
-	// API can return 200, but also have deleted_at or removed_at value.
-	deleted, err := checkSoftDelete(resp.Body())
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when attempting to check for soft delete, while reading group state: %s. Error: %s", groupName, err)
+	if asa.IsNotFound(err) {
+		log.Printf("[INFO] group %s does not exist.", groupName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading group %s", groupName)
 	}
 
-	if status == 200 && deleted == true {
+	// API can return 200, but also have deleted_at value.
+	if group.Deleted() {
 		log.Printf("[INFO] Group %s was removed Need to recreate.", groupName)
 		d.SetId("")
 		return nil
-	} else if status == 200 && deleted == false {
-		log.Printf("[INFO] Group %s exists.", groupName)
-		return nil
-	} else if status == 404 {
-		log.Printf("[INFO] group %s does not exist.", groupName)
-		d.SetId("")
-		return nil
-	} else {
-		return fmt.Errorf("[DEBUG] failed to read group state. Group: %s Status code: %d", groupName, status)
 	}
 
-}
+	log.Printf("[INFO] Group %s exists.", groupName)
 
-func resourceOKTAASACreateGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	return resourceOKTAASACreateGroupCreate(d, m)
+	d.Set("name", group.Name)
+	d.Set("roles", group.Roles)
+
+	return nil
 }
 
-func resourceOKTAASACreateGroupDelete(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASACreateGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 	groupName := d.Id()
 
-	resp, err := SendDelete(token.BearerToken, "/teams/"+teamName+"/groups/"+groupName, make([]byte, 0))
+	group := asa.Group{Name: groupName, Roles: expandStringSet(d.Get("roles").(*schema.Set))}
 
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when deleting group: %s. Error: %s", groupName, err)
+	log.Printf("[DEBUG] Setting roles of group %s to %v", groupName, group.Roles)
+
+	if err := client.UpdateGroup(ctx, group); err != nil {
+		return errorDiag(err, "Error when updating roles of group %s", groupName)
 	}
 
-	status := resp.StatusCode()
+	return resourceOKTAASACreateGroupRead(ctx, d, m)
+}
+
+func resourceOKTAASACreateGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	groupName := d.Id()
+
+	err := client.DeleteGroup(ctx, groupName)
 
-	if status < 300 || status == 404 {
+	if err == nil || asa.IsNotFound(err) {
 		log.Printf("[INFO] Group %s was successfully deleted", groupName)
 	} else {
-		return fmt.Errorf("[ERROR] Something went wrong while deleting group %s. Error: %s", d.Id(), resp)
+		return errorDiag(err, "Error when deleting group %s", groupName)
 	}
 
 	return nil
@@ -1,22 +1,25 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func TestAccGroup(t *testing.T) {
-	group := &Group{}
+	group := &asa.Group{}
 	groupName := "test-acc-group"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccGroupCheckDestroy(group),
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGroupCheckDestroy(group),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccGroupCreateConfig,
@@ -25,14 +28,84 @@ func TestAccGroup(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"oktaasa_create_group.test-group", "name", groupName,
 					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_create_group.test-group", "roles.#", "0",
+					),
+				),
+			},
+			{
+				Config:      testAccGroupInvalidRoleConfig,
+				ExpectError: regexp.MustCompile(`expected roles.* to be one of`),
+			},
+			{
+				Config: testAccGroupRolesConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccGroupCheckExists("oktaasa_create_group.test-group", group),
+					resource.TestCheckResourceAttr(
+						"oktaasa_create_group.test-group", "roles.#", "2",
+					),
+					resource.TestCheckTypeSetElemAttr(
+						"oktaasa_create_group.test-group", "roles.*", "access_user",
+					),
+					resource.TestCheckTypeSetElemAttr(
+						"oktaasa_create_group.test-group", "roles.*", "reporting_user",
+					),
 				),
 			},
-			//Note: OKTAASA does not allow a group name change once created (hence there is no Update step)
+			{
+				ResourceName:      "oktaasa_create_group.test-group",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			//Note: OKTAASA does not allow a group name change once created (hence name changes replace the group)
+		},
+	})
+}
+
+func TestAccGroup_existing(t *testing.T) {
+	groupName := "test-acc-group-existing"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGroupCheckUntouched(groupName, asa.RoleAccessAdmin),
+		Steps: []resource.TestStep{
+			{
+				// a group Terraform does not manage
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*asa.Client)
+					group := asa.Group{Name: groupName, Roles: []string{asa.RoleAccessAdmin}}
+					if err := client.CreateGroup(context.Background(), group); err != nil {
+						t.Fatalf("creating group %s: %s", groupName, err)
+					}
+				},
+				Config:      fmt.Sprintf(`resource "oktaasa_create_group" "test-group" { name = %q }`, groupName),
+				ExpectError: regexp.MustCompile("already exists, import it"),
+			},
 		},
 	})
 }
 
-func testAccGroupCheckExists(rn string, p *Group) resource.TestCheckFunc {
+// testAccGroupCheckUntouched checks that the group still has exactly the
+// given roles, then deletes it.
+func testAccGroupCheckUntouched(groupName string, roles ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		found, err := client.GetGroup(context.Background(), groupName)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		if !reflect.DeepEqual(found.Roles, roles) {
+			return fmt.Errorf("group %s has roles %v, expected %v", groupName, found.Roles, roles)
+		}
+
+		return client.DeleteGroup(context.Background(), groupName)
+	}
+}
+
+func testAccGroupCheckExists(rn string, p *asa.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		if !ok {
@@ -44,38 +117,31 @@ func testAccGroupCheckExists(rn string, p *Group) resource.TestCheckFunc {
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/groups/"+rs.Primary.ID)
+		found, err := client.GetGroup(context.Background(), rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccGroupCheckDestroy(p *Group) resource.TestCheckFunc {
+func testAccGroupCheckDestroy(p *asa.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/groups/"+p.Name)
-		if err != nil {
+		found, err := client.GetGroup(context.Background(), p.Name)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		status := resp.StatusCode()
-		deleted, err := checkSoftDelete(resp.Body())
-		if err != nil {
-			return fmt.Errorf("error while checking deleted status: %s", err)
-		}
-
-		if status == 200 && !deleted {
+		if !found.Deleted() {
 			return fmt.Errorf("group still exists")
 		}
 
@@ -87,3 +153,15 @@ const testAccGroupCreateConfig = `
 resource "oktaasa_create_group" "test-group" {
     name = "test-acc-group"
 }`
+
+const testAccGroupRolesConfig = `
+resource "oktaasa_create_group" "test-group" {
+    name = "test-acc-group"
+    roles = ["access_user", "reporting_user"]
+}`
+
+const testAccGroupInvalidRoleConfig = `
+resource "oktaasa_create_group" "test-group" {
+    name = "test-acc-group"
+    roles = ["superuser"]
+}`
@@ -1,30 +1,68 @@
 package oktaasa
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/hashicorp/terraform/helper/schema"
+	"context"
 	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func resourceOKTAASAToken() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOKTAASATokenCreate,
-		Read:   resourceOKTAASATokenRead,
-		Update: resourceOKTAASATokenUpdate,
-		Delete: resourceOKTAASATokenDelete,
+		CreateContext: resourceOKTAASATokenCreate,
+		ReadContext:   resourceOKTAASATokenRead,
+		UpdateContext: resourceOKTAASATokenUpdate,
+		DeleteContext: resourceOKTAASATokenDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOKTAASATokenImport,
+		},
+		CustomizeDiff: rotateAfterCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"project_name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
+			},
+			"keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rotate_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+			},
+			"pgp_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validatePGPKey,
 			},
 			// Computed
 			"token_value": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"encrypted_token_value": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issued_at": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
@@ -32,110 +70,97 @@ func resourceOKTAASAToken() *schema.Resource {
 	}
 }
 
-type EnrollmentToken struct {
-	Id    string `json:"id"`
-	Value string `json:"token"`
-}
-
-func resourceOKTAASATokenCreate(d *schema.ResourceData, m interface{}) error {
-	// Bearer session token
-	token := m.(Bearer)
+func resourceOKTAASATokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
 	//get project_name from terraform config.
 	projectName := d.Get("project_name").(string)
 	description := d.Get("description").(string)
 
-	// create project in OKTAASA
-	tokenDescription := map[string]interface{}{"description": description}
-	tokenDescriptionB, _ := json.Marshal(tokenDescription)
-
-	//make API call to create project
-	resp, err := SendPost(token.BearerToken, "/teams/"+teamName+"/projects/"+projectName+"/server_enrollment_tokens", tokenDescriptionB)
+	//make API call to create token
+	enrollmentToken, err := client.CreateEnrollmentToken(ctx, projectName, asa.EnrollmentToken{Description: description})
 
 	if err != nil {
-		return fmt.Errorf("[ERROR] Error when creating enrollment token: %s. Error: %s", description, err)
-	}
-
-	status := resp.StatusCode()
-
-	if status > 400 {
-		return fmt.Errorf("[ERROR] Unexpected error when creating token %d", status)
+		return errorDiag(err, "Error when creating enrollment token %q in project %s", description, projectName)
 	}
 
-	enrollmentToken := EnrollmentToken{}
+	// update resource ID with token ID.
+	d.SetId(enrollmentToken.ID)
 
-	jsonErr := json.Unmarshal(resp.Body(), &enrollmentToken)
-	if jsonErr != nil {
-		log.Printf("[DEBUG] Error storing enrollment token: %s", jsonErr)
+	// the value is only stored here, so that an imported token never has it
+	// in state; with a PGP key only the encrypted value is kept.
+	if pgpKey, ok := d.GetOk("pgp_key"); ok {
+		fingerprint, encrypted, err := encryptValue(pgpKey.(string), enrollmentToken.Token)
+		if err != nil {
+			return errorDiag(err, "Error when encrypting enrollment token %s", enrollmentToken.ID)
+		}
+		d.Set("key_fingerprint", fingerprint)
+		d.Set("encrypted_token_value", encrypted)
+	} else {
+		d.Set("token_value", enrollmentToken.Token)
 	}
 
-	// update resource ID with token ID.
-	d.SetId(enrollmentToken.Id)
-
-	return resourceOKTAASATokenRead(d, m)
+	return resourceOKTAASATokenRead(ctx, d, m)
 }
 
-func resourceOKTAASATokenRead(d *schema.ResourceData, m interface{}) error {
-	sessionToken := m.(Bearer)
+func resourceOKTAASATokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 	tokenId := d.Id()
 
 	//get project_name from terraform config.
 	projectName := d.Get("project_name").(string)
 
-	resp, err := SendGet(sessionToken.BearerToken, "/teams/"+teamName+"/projects/"+projectName+"/server_enrollment_tokens/"+tokenId)
+	tokenInfo, err := client.GetEnrollmentToken(ctx, projectName, tokenId)
 
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when reading token state. Token: %s. Error: %s", tokenId, err)
+	if asa.IsNotFound(err) {
+		log.Printf("[DEBUG] No token %s in this project", tokenId)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading enrollment token %s in project %s", tokenId, projectName)
 	}
 
-	status := resp.StatusCode()
+	log.Printf("[DEBUG] Token %s exists", tokenId)
 
-	if status == 200 {
-		log.Printf("[DEBUG] Token %s exists", tokenId)
+	d.Set("description", tokenInfo.Description)
+	d.Set("issued_at", tokenInfo.IssuedAt)
 
-		var tokenInfo EnrollmentToken
-		err := json.Unmarshal([]byte(resp.Body()), &tokenInfo)
+	return nil
+}
 
-		if err != nil {
-			return fmt.Errorf("[ERROR] Error when reading token state. Token: %s. Error: %s", tokenId, err)
-		}
+// resourceOKTAASATokenImport imports an enrollment token from an ID of the
+// form project/token_id. The token value is not imported.
+func resourceOKTAASATokenImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportID(d.Id(), "project/token_id")
+	if err != nil {
+		return nil, err
+	}
 
-		d.Set("token_value", tokenInfo.Value)
+	d.Set("project_name", parts[0])
+	d.SetId(parts[1])
 
-	} else if status == 404 {
-		log.Printf("[DEBUG] No token %s in this project", tokenId)
-		d.SetId("")
-		return nil
-	} else {
-		return fmt.Errorf("[ERROR] Something went wrong while retrieving a list of enrollment tokens. Error: %s", resp)
-	}
-	return nil
+	return []*schema.ResourceData{d}, nil
 }
 
-func resourceOKTAASATokenUpdate(d *schema.ResourceData, m interface{}) error {
-	// not possible to update token.
-	return nil
+func resourceOKTAASATokenUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// not possible to update token. Every other attribute forces a new token,
+	// so only rotate_after, which is not sent to the API, can change here.
+	return resourceOKTAASATokenRead(ctx, d, m)
 }
 
-func resourceOKTAASATokenDelete(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASATokenDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
 	//get project_name from terraform config.
 	projectName := d.Get("project_name").(string)
 	tokenId := d.Id()
 
-	resp, err := SendDelete(token.BearerToken, "/teams/"+teamName+"/projects/"+projectName+"/server_enrollment_tokens/"+tokenId, make([]byte, 0))
-
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when deleting token: %s. Error: %s", tokenId, err)
-	}
-
-	status := resp.StatusCode()
+	err := client.DeleteEnrollmentToken(ctx, projectName, tokenId)
 
-	if status < 300 || status == 404 {
+	if err == nil || asa.IsNotFound(err) {
 		log.Printf("[INFO] Enrollment token %s of a project %s was successfully deleted", d.Id(), projectName)
 	} else {
-		return fmt.Errorf("[ERROR] Error while deleting token: %s", resp)
+		return errorDiag(err, "Error when deleting enrollment token %s in project %s", tokenId, projectName)
 	}
 
 	return nil
@@ -1,24 +1,25 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func TestAccTkn(t *testing.T) {
-	//tkn := &EnrollmentToken{}
+	//tkn := &asa.EnrollmentToken{}
 	//tknName := "test-acc-token"
 	projectName := "test-acc-project2"
-	project := &Project{}
+	project := &asa.Project{}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccProjectCheckDestroy2(project),
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccProjectCheckDestroy2(project),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccProjectTokenCreateConfig2,
@@ -41,12 +42,107 @@ func TestAccTkn(t *testing.T) {
 					),
 				),
 			},
+			{
+				ResourceName:      "oktaasa_enrollment_token.test-token",
+				ImportState:       true,
+				ImportStateIdFunc: testAccProjectScopedImportID("oktaasa_enrollment_token.test-token"),
+				ImportStateVerify: true,
+				// only stored when the token is created
+				ImportStateVerifyIgnore: []string{"token_value"},
+			},
 			//Note: OKTAASA does not allow token or token description changes once created (hence there is no Update step)
 		},
 	})
 }
 
-func testAccProjectCheckExists2(rn string, p *Project) resource.TestCheckFunc {
+func TestAccTkn_replace(t *testing.T) {
+	var tokenID string
+	rn := "oktaasa_enrollment_token.test-token"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTknRotateConfig("Token for TestAcc", "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTknCheckReplaced(rn, &tokenID),
+					resource.TestCheckResourceAttrSet(rn, "issued_at"),
+				),
+			},
+			{
+				Config: testAccTknRotateConfig("Token for TestAcc", "2"),
+				Check:  testAccTknCheckReplaced(rn, &tokenID),
+			},
+			{
+				Config: testAccTknRotateConfig("Token for TestAcc, renamed", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTknCheckReplaced(rn, &tokenID),
+					resource.TestCheckResourceAttr(rn, "description", "Token for TestAcc, renamed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTkn_pgpKey(t *testing.T) {
+	_, pgpKey := testPGPKey(t)
+	rn := "oktaasa_enrollment_token.test-token"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTknPGPConfig(pgpKey),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr(rn, "token_value"),
+					resource.TestCheckResourceAttrSet(rn, "encrypted_token_value"),
+					resource.TestCheckResourceAttrSet(rn, "key_fingerprint"),
+				),
+			},
+			{
+				// the plaintext token is not written to state on import
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateIdFunc: testAccProjectScopedImportID(rn),
+				ImportStateCheck:  testAccTknCheckImportedWithoutValue,
+			},
+		},
+	})
+}
+
+// testAccTknCheckImportedWithoutValue checks that no imported token has its
+// value in state.
+func testAccTknCheckImportedWithoutValue(states []*terraform.InstanceState) error {
+	for _, is := range states {
+		if v := is.Attributes["token_value"]; v != "" {
+			return fmt.Errorf("token %s was imported with its value", is.ID)
+		}
+	}
+
+	return nil
+}
+
+// testAccTknCheckReplaced checks that the token ID differs from the one seen
+// by the previous check and records it.
+func testAccTknCheckReplaced(rn string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		if rs.Primary.ID == *id {
+			return fmt.Errorf("token %s was not replaced", *id)
+		}
+		*id = rs.Primary.ID
+
+		return nil
+	}
+}
+
+func testAccProjectCheckExists2(rn string, p *asa.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		if !ok {
@@ -58,38 +154,31 @@ func testAccProjectCheckExists2(rn string, p *Project) resource.TestCheckFunc {
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+rs.Primary.ID)
+		found, err := client.GetProject(context.Background(), rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccProjectCheckDestroy2(p *Project) resource.TestCheckFunc {
+func testAccProjectCheckDestroy2(p *asa.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+p.Name)
-		if err != nil {
+		found, err := client.GetProject(context.Background(), p.Name)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		status := resp.StatusCode()
-		deleted, err := checkSoftDelete(resp.Body())
-		if err != nil {
-			return fmt.Errorf("error while checking deleted status: %s", err)
-		}
-
-		if status == 200 && !deleted {
+		if !found.Deleted() {
 			return fmt.Errorf("project still exists")
 		}
 
@@ -97,7 +186,7 @@ func testAccProjectCheckDestroy2(p *Project) resource.TestCheckFunc {
 	}
 }
 
-func testAccTknCheckExists(rn string, p *EnrollmentToken) resource.TestCheckFunc {
+func testAccTknCheckExists(rn string, p *asa.EnrollmentToken) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		projectName := "test-acc-project2"
@@ -111,42 +200,32 @@ func testAccTknCheckExists(rn string, p *EnrollmentToken) resource.TestCheckFunc
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+projectName+"/server_enrollment_tokens/"+rs.Primary.ID)
+		found, err := client.GetEnrollmentToken(context.Background(), projectName, rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccTknCheckDestroy(p *EnrollmentToken) resource.TestCheckFunc {
+func testAccTknCheckDestroy(p *asa.EnrollmentToken) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		config := testAccProvider.Meta().(Bearer)
+		projectName := "test-acc-project2"
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/server_enrollment_tokens/"+p.Id)
-		if err != nil {
+		_, err := client.GetEnrollmentToken(context.Background(), projectName, p.ID)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		status := resp.StatusCode()
-		deleted, err := checkSoftDelete(resp.Body())
-		if err != nil {
-			return fmt.Errorf("error while checking deleted status: %s", err)
-		}
-
-		if status == 200 && !deleted {
-			return fmt.Errorf("token still exists")
-		}
-
-		return nil
+		return fmt.Errorf("token still exists")
 	}
 }
 
@@ -161,3 +240,33 @@ resource "oktaasa_enrollment_token" "test-token" {
     project_name = oktaasa_project.test.project_name
   	description = "Token for TestAcc"
 }`
+
+func testAccTknRotateConfig(description, revision string) string {
+	return fmt.Sprintf(`
+resource "oktaasa_project" "test" {
+    project_name = "test-acc-project-rotate"
+}
+
+resource "oktaasa_enrollment_token" "test-token" {
+    project_name = oktaasa_project.test.project_name
+  	description = %q
+  	rotate_after = "24h"
+
+  	keepers = {
+  	  revision = %q
+  	}
+}`, description, revision)
+}
+
+func testAccTknPGPConfig(pgpKey string) string {
+	return fmt.Sprintf(`
+resource "oktaasa_project" "test" {
+    project_name = "test-acc-project-pgp"
+}
+
+resource "oktaasa_enrollment_token" "test-token" {
+    project_name = oktaasa_project.test.project_name
+  	description = "Token for TestAcc"
+  	pgp_key = %q
+}`, pgpKey)
+}
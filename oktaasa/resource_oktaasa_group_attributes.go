@@ -0,0 +1,131 @@
+package oktaasa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+// groupAttributeNames are the group attributes that can be managed by
+// oktaasa_group_attributes, which are also its schema keys.
+var groupAttributeNames = []string{
+	asa.AttributeUnixGroupName,
+	asa.AttributeUnixGID,
+	asa.AttributeWindowsGroupName,
+}
+
+func resourceOKTAASAGroupAttributes() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOKTAASAGroupAttributesCreate,
+		ReadContext:   resourceOKTAASAGroupAttributesRead,
+		UpdateContext: resourceOKTAASAGroupAttributesUpdate,
+		DeleteContext: resourceOKTAASAGroupAttributesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOKTAASAGroupAttributesImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			asa.AttributeUnixGroupName: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			asa.AttributeUnixGID: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(100, 2147483647),
+			},
+			asa.AttributeWindowsGroupName: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceOKTAASAGroupAttributesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	//get group_name from terraform config.
+	groupName := d.Get("group_name").(string)
+
+	log.Printf("[DEBUG] Setting attributes of group %s", groupName)
+
+	if diags := groupAttributeOwner(client, groupName).apply(ctx, d, groupAttributeNames); diags != nil {
+		return diags
+	}
+
+	d.SetId(groupName)
+
+	log.Printf("[DEBUG] Success. Attributes of group %s were set", groupName)
+
+	return resourceOKTAASAGroupAttributesRead(ctx, d, m)
+}
+
+func resourceOKTAASAGroupAttributesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	groupName := d.Id()
+
+	found, diags := groupAttributeOwner(client, groupName).read(ctx, d, managedAttributes(d, groupAttributeNames))
+	if diags != nil {
+		return diags
+	}
+
+	if !found {
+		log.Printf("[INFO] Group %s does not exist", groupName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("group_name", groupName)
+
+	return nil
+}
+
+func resourceOKTAASAGroupAttributesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	groupName := d.Id()
+
+	log.Printf("[DEBUG] Updating attributes of group %s", groupName)
+
+	if diags := groupAttributeOwner(client, groupName).apply(ctx, d, groupAttributeNames); diags != nil {
+		return diags
+	}
+
+	return resourceOKTAASAGroupAttributesRead(ctx, d, m)
+}
+
+// resourceOKTAASAGroupAttributesDelete removes the managed attributes, so ASA
+// goes back to choosing the group's names and GID.
+func resourceOKTAASAGroupAttributesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	groupName := d.Id()
+
+	if diags := groupAttributeOwner(client, groupName).removeAll(ctx, managedAttributes(d, groupAttributeNames)); diags != nil {
+		return diags
+	}
+
+	log.Printf("[INFO] Attributes of group %s were successfully removed", groupName)
+
+	return nil
+}
+
+// resourceOKTAASAGroupAttributesImport imports the attributes the group has,
+// which the imported resource then manages.
+func resourceOKTAASAGroupAttributesImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*asa.Client)
+
+	if err := groupAttributeOwner(client, d.Id()).importAll(ctx, d, groupAttributeNames); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
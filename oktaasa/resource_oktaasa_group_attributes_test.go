@@ -0,0 +1,213 @@
+package oktaasa
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func TestAccGroupAttributes(t *testing.T) {
+	groupName := "test-acc-group-attributes"
+	rn := "oktaasa_group_attributes.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGroupAttributesCheckDestroy(groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupAttributesConfig(groupName, "legacy-ops", 61001, "legacy-ops"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupAttributesCheckExists(rn, asa.AttributeUnixGID, "61001"),
+					resource.TestCheckResourceAttr(rn, "group_name", groupName),
+					resource.TestCheckResourceAttr(rn, "unix_group_name", "legacy-ops"),
+					resource.TestCheckResourceAttr(rn, "unix_gid", "61001"),
+					resource.TestCheckResourceAttr(rn, "windows_group_name", "legacy-ops"),
+				),
+			},
+			{
+				Config: testAccGroupAttributesConfig(groupName, "legacy-ops", 61002, "legacy-ops"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupAttributesCheckExists(rn, asa.AttributeUnixGID, "61002"),
+					resource.TestCheckResourceAttr(rn, "unix_gid", "61002"),
+				),
+			},
+			{
+				// a GID changed outside of Terraform is set back
+				PreConfig: func() {
+					testAccGroupAttributesSet(t, groupName, asa.AttributeUnixGID, 61999)
+				},
+				Config: testAccGroupAttributesConfig(groupName, "legacy-ops", 61002, "legacy-ops"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupAttributesCheckExists(rn, asa.AttributeUnixGID, "61002"),
+				),
+			},
+			{
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// an attribute dropped from the configuration is removed
+				Config: testAccGroupAttributesConfig(groupName, "legacy-ops", 61002, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupAttributesCheckAbsent(groupName, asa.AttributeWindowsGroupName),
+					resource.TestCheckResourceAttr(rn, "windows_group_name", ""),
+				),
+			},
+			{
+				// destroying the resource keeps attributes set outside of
+				// Terraform
+				PreConfig: func() {
+					testAccGroupAttributesSet(t, groupName, asa.AttributeWindowsGroupName, "legacy-ops-win")
+				},
+				Config: testAccGroupAttributesConfig(groupName, "", 0, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupAttributesCheckAbsent(groupName, asa.AttributeUnixGroupName, asa.AttributeUnixGID),
+					testAccGroupAttributesCheckValue(groupName, asa.AttributeWindowsGroupName, "legacy-ops-win"),
+				),
+			},
+		},
+	})
+}
+
+// testAccGroupAttributesSet sets an attribute of a group, as if it was done in
+// the ASA console.
+func testAccGroupAttributesSet(t *testing.T, groupName, name string, value interface{}) {
+	client := testAccProvider.Meta().(*asa.Client)
+
+	attributes, err := client.GroupAttributes(context.Background(), groupName)
+	if err != nil {
+		t.Fatalf("reading attributes of group %s: %s", groupName, err)
+	}
+
+	for _, a := range attributes {
+		if a.Name == name {
+			a.Value = value
+			if err := client.UpdateGroupAttribute(context.Background(), groupName, a); err != nil {
+				t.Fatalf("setting %s of group %s: %s", name, groupName, err)
+			}
+			return
+		}
+	}
+
+	if err := client.CreateGroupAttribute(context.Background(), groupName, asa.Attribute{Name: name, Value: value}); err != nil {
+		t.Fatalf("setting %s of group %s: %s", name, groupName, err)
+	}
+}
+
+// testAccGroupAttributesCheckExists checks that the group in state has the
+// attribute name set to value in ASA.
+func testAccGroupAttributesCheckExists(rn, name, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		// resource ID is the group name
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("resource id not set")
+		}
+
+		return testAccGroupAttributesCheckValue(rs.Primary.ID, name, value)(s)
+	}
+}
+
+// testAccGroupAttributesCheckValue checks that the group has the attribute
+// name set to value in ASA.
+func testAccGroupAttributesCheckValue(groupName, name, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		attributes, err := client.GroupAttributes(context.Background(), groupName)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		for _, a := range attributes {
+			if a.Name == name {
+				if a.String() != value {
+					return fmt.Errorf("%s of group %s is %s, expected %s", name, groupName, a.String(), value)
+				}
+				return nil
+			}
+		}
+
+		return fmt.Errorf("group %s has no %s", groupName, name)
+	}
+}
+
+// testAccGroupAttributesCheckAbsent checks that the group has none of the
+// given attributes in ASA.
+func testAccGroupAttributesCheckAbsent(groupName string, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		attributes, err := client.GroupAttributes(context.Background(), groupName)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		for _, a := range attributes {
+			for _, name := range names {
+				if a.Name == name {
+					return fmt.Errorf("group %s still has %s", groupName, name)
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccGroupAttributesCheckDestroy passes once the group is gone, as it is
+// destroyed along with its attributes.
+func testAccGroupAttributesCheckDestroy(groupName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		attributes, err := client.GroupAttributes(context.Background(), groupName)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		for _, a := range attributes {
+			for _, name := range groupAttributeNames {
+				if a.Name == name {
+					return fmt.Errorf("group %s still has %s", groupName, name)
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccGroupAttributesConfig leaves out the oktaasa_group_attributes
+// resource when unixGroupName is empty.
+func testAccGroupAttributesConfig(groupName, unixGroupName string, gid int, windowsGroupName string) string {
+	config := fmt.Sprintf(`
+resource "oktaasa_create_group" "test" {
+    name = %q
+}`, groupName)
+
+	if unixGroupName == "" {
+		return config
+	}
+
+	return config + fmt.Sprintf(`
+
+resource "oktaasa_group_attributes" "test" {
+    group_name = oktaasa_create_group.test.name
+    unix_group_name = %q
+    unix_gid = %d
+    windows_group_name = %q != "" ? %q : null
+}`, unixGroupName, gid, windowsGroupName, windowsGroupName)
+}
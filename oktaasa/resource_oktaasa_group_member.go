@@ -0,0 +1,112 @@
+package oktaasa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func resourceOKTAASAGroupMember() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOKTAASAGroupMemberCreate,
+		ReadContext:   resourceOKTAASAGroupMemberRead,
+		DeleteContext: resourceOKTAASAGroupMemberDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOKTAASAGroupMemberImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"user_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceOKTAASAGroupMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	//get settings from terraform config.
+	groupName := d.Get("group_name").(string)
+	userName := d.Get("user_name").(string)
+
+	log.Printf("[DEBUG] Adding user %s to group %s", userName, groupName)
+
+	if err := client.AddGroupUser(ctx, groupName, userName); err != nil {
+		return errorDiag(err, "Error when adding user %s to group %s", userName, groupName)
+	}
+
+	d.SetId(groupName + "/" + userName)
+
+	log.Printf("[DEBUG] Success. User %s was added to group %s", userName, groupName)
+
+	return resourceOKTAASAGroupMemberRead(ctx, d, m)
+}
+
+func resourceOKTAASAGroupMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	parts, err := splitImportID(d.Id(), "group/user")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	groupName, userName := parts[0], parts[1]
+
+	members, err := client.GroupUserNames(ctx, groupName)
+
+	if asa.IsNotFound(err) {
+		log.Printf("[INFO] Group %s does not exist", groupName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading members of group %s", groupName)
+	}
+
+	if !flattenStringSet(members).Contains(userName) {
+		log.Printf("[INFO] User %s is not a member of group %s", userName, groupName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("group_name", groupName)
+	d.Set("user_name", userName)
+
+	return nil
+}
+
+func resourceOKTAASAGroupMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	groupName := d.Get("group_name").(string)
+	userName := d.Get("user_name").(string)
+
+	err := client.RemoveGroupUser(ctx, groupName, userName)
+
+	if err == nil || asa.IsNotFound(err) {
+		log.Printf("[INFO] User %s was successfully removed from group %s", userName, groupName)
+	} else {
+		return errorDiag(err, "Error when removing user %s from group %s", userName, groupName)
+	}
+
+	return nil
+}
+
+// resourceOKTAASAGroupMemberImport imports a group member from an ID of the
+// form group/user, which is also the resource ID.
+func resourceOKTAASAGroupMemberImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := splitImportID(d.Id(), "group/user"); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
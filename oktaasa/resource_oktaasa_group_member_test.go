@@ -0,0 +1,59 @@
+package oktaasa
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupMember(t *testing.T) {
+	groupName := "test-acc-group-member"
+	rn := "oktaasa_group_member.a"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGroupMembershipCheckDestroy(groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupMembershipConfig(groupName, testAccGroupMemberConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupMembershipCheckMembers(groupName, groupName+"-a", groupName+"-b"),
+					resource.TestCheckResourceAttr(rn, "id", groupName+"/"+groupName+"-a"),
+					resource.TestCheckResourceAttr("oktaasa_group_member.b", "id", groupName+"/"+groupName+"-b"),
+				),
+			},
+			{
+				// a member added elsewhere is left alone
+				PreConfig: func() {
+					testAccGroupMembershipAdd(t, groupName, groupName+"-c")
+				},
+				Config:   testAccGroupMembershipConfig(groupName, testAccGroupMemberConfig),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// removing the resources only removes their users
+				Config: testAccGroupMembershipConfig(groupName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupMembershipCheckMembers(groupName, groupName+"-c"),
+				),
+			},
+		},
+	})
+}
+
+const testAccGroupMemberConfig = `
+resource "oktaasa_group_member" "a" {
+    group_name = oktaasa_create_group.test.name
+    user_name = oktaasa_service_user.a.name
+}
+
+resource "oktaasa_group_member" "b" {
+    group_name = oktaasa_create_group.test.name
+    user_name = oktaasa_service_user.b.name
+}`
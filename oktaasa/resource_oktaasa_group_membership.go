@@ -0,0 +1,136 @@
+package oktaasa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func resourceOKTAASAGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOKTAASAGroupMembershipCreate,
+		ReadContext:   resourceOKTAASAGroupMembershipRead,
+		UpdateContext: resourceOKTAASAGroupMembershipUpdate,
+		DeleteContext: resourceOKTAASAGroupMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"users": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				// matches flattenStringSet, so members can be compared.
+				Set: schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceOKTAASAGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	//get group_name from terraform config.
+	groupName := d.Get("group_name").(string)
+
+	log.Printf("[DEBUG] Setting members of group %s", groupName)
+
+	if diags := resourceOKTAASAGroupMembershipApply(ctx, d, client); diags != nil {
+		return diags
+	}
+
+	d.SetId(groupName)
+
+	log.Printf("[DEBUG] Success. Members of group %s were set", groupName)
+
+	return resourceOKTAASAGroupMembershipRead(ctx, d, m)
+}
+
+func resourceOKTAASAGroupMembershipRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	groupName := d.Id()
+
+	members, err := client.GroupUserNames(ctx, groupName)
+
+	if asa.IsNotFound(err) {
+		log.Printf("[INFO] Group %s does not exist", groupName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading members of group %s", groupName)
+	}
+
+	d.Set("group_name", groupName)
+	d.Set("users", members)
+
+	return nil
+}
+
+func resourceOKTAASAGroupMembershipUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	log.Printf("[DEBUG] Updating members of group %s", d.Id())
+
+	if diags := resourceOKTAASAGroupMembershipApply(ctx, d, client); diags != nil {
+		return diags
+	}
+
+	return resourceOKTAASAGroupMembershipRead(ctx, d, m)
+}
+
+// resourceOKTAASAGroupMembershipDelete removes all members from the group.
+func resourceOKTAASAGroupMembershipDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	groupName := d.Get("group_name").(string)
+
+	for _, userName := range expandStringSet(d.Get("users").(*schema.Set)) {
+		err := client.RemoveGroupUser(ctx, groupName, userName)
+		if err != nil && !asa.IsNotFound(err) {
+			return errorDiag(err, "Error when removing user %s from group %s", userName, groupName)
+		}
+	}
+
+	log.Printf("[INFO] Members were successfully removed from group %s", groupName)
+
+	return nil
+}
+
+// resourceOKTAASAGroupMembershipApply adds the configured users that are not
+// members yet and removes every member that is not configured.
+func resourceOKTAASAGroupMembershipApply(ctx context.Context, d *schema.ResourceData, client *asa.Client) diag.Diagnostics {
+	groupName := d.Get("group_name").(string)
+
+	members, err := client.GroupUserNames(ctx, groupName)
+	if err != nil {
+		return errorDiag(err, "Error when reading members of group %s", groupName)
+	}
+	current := flattenStringSet(members)
+
+	wanted := d.Get("users").(*schema.Set)
+
+	for _, userName := range expandStringSet(wanted.Difference(current)) {
+		log.Printf("[DEBUG] Adding user %s to group %s", userName, groupName)
+		if err := client.AddGroupUser(ctx, groupName, userName); err != nil {
+			return errorDiag(err, "Error when adding user %s to group %s", userName, groupName)
+		}
+	}
+
+	for _, userName := range expandStringSet(current.Difference(wanted)) {
+		log.Printf("[DEBUG] Removing user %s from group %s", userName, groupName)
+		err := client.RemoveGroupUser(ctx, groupName, userName)
+		if err != nil && !asa.IsNotFound(err) {
+			return errorDiag(err, "Error when removing user %s from group %s", userName, groupName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package oktaasa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func TestAccGroupMembership(t *testing.T) {
+	groupName := "test-acc-group-membership"
+	rn := "oktaasa_group_membership.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGroupMembershipCheckDestroy(groupName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupMembershipConfig(groupName, testAccGroupMembershipUsers("oktaasa_service_user.a.name, oktaasa_service_user.b.name")),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupMembershipCheckMembers(groupName, groupName+"-a", groupName+"-b"),
+					resource.TestCheckResourceAttr(rn, "id", groupName),
+					resource.TestCheckResourceAttr(rn, "users.#", "2"),
+				),
+			},
+			{
+				// a member added elsewhere is removed
+				PreConfig: func() {
+					testAccGroupMembershipAdd(t, groupName, groupName+"-c")
+				},
+				Config: testAccGroupMembershipConfig(groupName, testAccGroupMembershipUsers("oktaasa_service_user.a.name, oktaasa_service_user.b.name")),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupMembershipCheckMembers(groupName, groupName+"-a", groupName+"-b"),
+				),
+			},
+			{
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccGroupMembershipConfig(groupName, testAccGroupMembershipUsers("oktaasa_service_user.a.name")),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGroupMembershipCheckMembers(groupName, groupName+"-a"),
+					resource.TestCheckResourceAttr(rn, "users.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccGroupMembershipAdd adds a user to a group, as if it was done in the
+// ASA console.
+func testAccGroupMembershipAdd(t *testing.T, groupName, userName string) {
+	client := testAccProvider.Meta().(*asa.Client)
+
+	if err := client.AddGroupUser(context.Background(), groupName, userName); err != nil {
+		t.Fatalf("adding user %s to group %s: %s", userName, groupName, err)
+	}
+}
+
+// testAccGroupMembershipCheckMembers checks that the group has exactly the
+// given members, in order.
+func testAccGroupMembershipCheckMembers(groupName string, userNames ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		members, err := client.GroupUserNames(context.Background(), groupName)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		if !reflect.DeepEqual(members, userNames) {
+			return fmt.Errorf("group %s has members %v, expected %v", groupName, members, userNames)
+		}
+
+		return nil
+	}
+}
+
+// testAccGroupMembershipCheckDestroy passes once the group is gone, as it is
+// destroyed along with its members.
+func testAccGroupMembershipCheckDestroy(groupName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		members, err := client.GroupUserNames(context.Background(), groupName)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		if len(members) != 0 {
+			return fmt.Errorf("group %s still has members %v", groupName, members)
+		}
+
+		return nil
+	}
+}
+
+func testAccGroupMembershipUsers(users string) string {
+	return fmt.Sprintf(`
+resource "oktaasa_group_membership" "test" {
+    group_name = oktaasa_create_group.test.name
+    users = [%s]
+}`, users)
+}
+
+// testAccGroupMembershipConfig creates a group and three service users to add
+// to it with membership. The users are named after the group, as ASA does not
+// delete service users and they cannot be created again.
+func testAccGroupMembershipConfig(groupName, membership string) string {
+	return fmt.Sprintf(`
+resource "oktaasa_create_group" "test" {
+    name = %[1]q
+}
+
+resource "oktaasa_service_user" "a" {
+    name = "%[1]s-a"
+}
+
+resource "oktaasa_service_user" "b" {
+    name = "%[1]s-b"
+}
+
+resource "oktaasa_service_user" "c" {
+    name = "%[1]s-c"
+}
+%[2]s`, groupName, membership)
+}
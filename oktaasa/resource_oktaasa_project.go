@@ -1,173 +1,299 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/hashicorp/terraform/helper/schema"
 	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func resourceOKTAASAProject() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOKTAASAProjectCreate,
-		Read:   resourceOKTAASAProjectRead,
-		Update: resourceOKTAASAProjectUpdate,
-		Delete: resourceOKTAASAProjectDelete,
+		CreateContext: resourceOKTAASAProjectCreate,
+		ReadContext:   resourceOKTAASAProjectRead,
+		UpdateContext: resourceOKTAASAProjectUpdate,
+		DeleteContext: resourceOKTAASAProjectDelete,
+		CustomizeDiff: resourceOKTAASAProjectCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"project_name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"next_unix_uid": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  60101,
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          60101,
+				DiffSuppressFunc: suppressAdvancedCounter,
 			},
 			"next_unix_gid": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          63001,
+				DiffSuppressFunc: suppressAdvancedCounter,
+			},
+			"create_server_users": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"require_preauthorization": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"ssh_certificate_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					asa.SSHCertificateTypeED25519,
+					asa.SSHCertificateTypeRSA,
+				}, false),
+			},
+			"user_on_demand_period": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"force_shared_ssh_users": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"shared_admin_user_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"forward_traffic": {
+				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  63001,
+				Default:  false,
+			},
+			"gateway_selector": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGatewaySelector,
+			},
+			"ssh_session_recording": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"rdp_session_recording": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
-func resourceOKTAASAProjectCreate(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASAProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
 	//get project_name from terraform config.
 	project_name := d.Get("project_name").(string)
-	next_unix_uid := d.Get("next_unix_uid")
-	next_unix_gid := d.Get("next_unix_gid")
 
 	// create project in OKTAASA
-	project := map[string]interface{}{"name": project_name,
-		"create_server_users": true,
-		"next_unix_uid":       next_unix_uid,
-		"next_unix_gid":       next_unix_gid}
-	projectB, _ := json.Marshal(project)
+	project := expandProject(d)
 
-	d.SetId(project_name)
-	log.Printf("[DEBUG] Project POST body: %s", projectB)
+	log.Printf("[DEBUG] Project POST body: %+v", project)
 
 	//make API call to create project
-	resp, err := SendPost(token.BearerToken, "/teams/"+teamName+"/projects", projectB)
+	err := client.CreateProject(ctx, project)
 
 	if err != nil {
-		return fmt.Errorf("[ERROR] Error when creating project: %s. Error: %s", project_name, err)
-	}
-
-	status := resp.StatusCode()
-
-	if status == 201 {
-		log.Printf("[INFO] Project %s was successfully created", project_name)
-	} else {
-		log.Printf("[ERROR] Something went wrong while creating project. Error: %s", resp)
+		return errorDiag(err, "Error when creating project %s", project_name)
 	}
 
-	return resourceOKTAASAProjectRead(d, m)
-}
+	log.Printf("[INFO] Project %s was successfully created", project_name)
 
-type ProjectList struct {
-	Projects []Project `json:"list"`
-}
+	d.SetId(project_name)
 
-type Project struct {
-	Name      string `json:"name"`
-	DeletedAt string `json:"deleted_at"`
+	return resourceOKTAASAProjectRead(ctx, d, m)
 }
 
-func resourceOKTAASAProjectRead(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASAProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 	projectName := d.Id()
 
-	resp, err := SendGet(token.BearerToken, "/teams/"+teamName+"/projects/"+projectName)
+	project, err := client.GetProject(ctx, projectName)
 
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when reading project state: %s. Error: %s", projectName, err)
-	}
-
-	status := resp.StatusCode()
-
-	// API can return 200, but also have deleted_at or removed_at value.
-	deleted, err := checkSoftDelete(resp.Body())
-
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error when attempting to check for soft delete, while reading project state: %s. Error: %s", projectName, err)
+	if asa.IsNotFound(err) {
+		log.Printf("[INFO] Project %s does not exist", projectName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading project %s", projectName)
 	}
 
-	if status == 200 && deleted == true {
+	// API can return 200, but also have deleted_at value.
+	if project.Deleted() {
 		log.Printf("[INFO] Project %s was removed.", projectName)
 		d.SetId("")
 		return nil
-	} else if status == 200 && deleted == false {
-		log.Printf("[INFO] Project %s exists.", projectName)
-		return nil
-	} else if status == 404 {
-		log.Printf("[INFO] Project %s does not exist", projectName)
-		d.SetId("")
-		return nil
+	}
+
+	log.Printf("[INFO] Project %s exists.", projectName)
+
+	d.Set("project_name", project.Name)
+	d.Set("next_unix_uid", project.NextUnixUID)
+	d.Set("next_unix_gid", project.NextUnixGID)
+	d.Set("create_server_users", project.CreateServerUsers)
+	d.Set("require_preauthorization", project.RequirePreauthorization)
+	d.Set("ssh_certificate_type", project.SSHCertificateType)
+	d.Set("force_shared_ssh_users", project.ForceSharedSSHUsers)
+	d.Set("forward_traffic", project.ForwardTraffic)
+	d.Set("gateway_selector", project.GatewaySelector)
+	d.Set("ssh_session_recording", project.SSHSessionRecording)
+	d.Set("rdp_session_recording", project.RDPSessionRecording)
+
+	if project.UserOnDemandPeriod != nil {
+		d.Set("user_on_demand_period", *project.UserOnDemandPeriod)
 	} else {
-		return fmt.Errorf("[DEBUG] failed to read project state. Project: %s Status code: %d", projectName, status)
+		d.Set("user_on_demand_period", 0)
 	}
+	if project.SharedAdminUserName != nil {
+		d.Set("shared_admin_user_name", *project.SharedAdminUserName)
+	} else {
+		d.Set("shared_admin_user_name", "")
+	}
+
+	return nil
 }
 
-func resourceOKTAASAProjectUpdate(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+// expandProject builds the project settings sent on create and update from
+// the configuration.
+func expandProject(d *schema.ResourceData) asa.Project {
+	project := asa.Project{
+		Name:                    d.Get("project_name").(string),
+		NextUnixUID:             d.Get("next_unix_uid").(int),
+		NextUnixGID:             d.Get("next_unix_gid").(int),
+		CreateServerUsers:       d.Get("create_server_users").(bool),
+		RequirePreauthorization: d.Get("require_preauthorization").(bool),
+		SSHCertificateType:      d.Get("ssh_certificate_type").(string),
+		ForceSharedSSHUsers:     d.Get("force_shared_ssh_users").(bool),
+		ForwardTraffic:          d.Get("forward_traffic").(bool),
+		GatewaySelector:         d.Get("gateway_selector").(string),
+		SSHSessionRecording:     d.Get("ssh_session_recording").(bool),
+		RDPSessionRecording:     d.Get("rdp_session_recording").(bool),
+	}
 
-	//get project_name from terraform config.
-	projectName := d.Get("project_name").(string)
-	nextUnixUid := d.Get("next_unix_uid")
-	nextUnixGid := d.Get("next_unix_gid")
+	if v, ok := d.GetOk("user_on_demand_period"); ok {
+		period := v.(int)
+		project.UserOnDemandPeriod = &period
+	}
+	if v, ok := d.GetOk("shared_admin_user_name"); ok {
+		name := v.(string)
+		project.SharedAdminUserName = &name
+	}
 
-	// create project in OKTAASA
-	project := map[string]interface{}{"name": projectName,
-		"create_server_users": true,
-		"next_unix_uid":       nextUnixUid,
-		"next_unix_gid":       nextUnixGid}
-	projectB, _ := json.Marshal(project)
+	return project
+}
 
-	d.SetId(projectName)
-	log.Printf("[DEBUG] Project POST body: %s", projectB)
+// gatewayLabelPattern matches one key=value label of a gateway selector.
+var gatewayLabelPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.-]*[A-Za-z0-9])?=[A-Za-z0-9]([A-Za-z0-9_.-]*[A-Za-z0-9])?$`)
 
-	//make API call to create project
-	resp, err := SendPut(token.BearerToken, "/teams/"+teamName+"/projects/"+projectName, projectB)
+// validateGatewaySelector checks that a gateway selector is a comma-separated
+// list of key=value labels, such as "env=prod,region=us-east-1". An empty
+// selector matches no gateway.
+func validateGatewaySelector(v interface{}, k string) (ws []string, errs []error) {
+	if v.(string) == "" {
+		return
+	}
+	for _, label := range strings.Split(v.(string), ",") {
+		if !gatewayLabelPattern.MatchString(strings.TrimSpace(label)) {
+			errs = append(errs, fmt.Errorf("%q must be a comma-separated list of key=value labels, got invalid label %q", k, label))
+		}
+	}
+	return
+}
 
-	if err != nil {
-		return fmt.Errorf("[ERROR] Error updating project settings. Project: %s. Error: %s", projectName, err)
+// resourceOKTAASAProjectCustomizeDiff rejects settings ASA would refuse:
+// session recording needs traffic forwarding, which needs a gateway selector.
+func resourceOKTAASAProjectCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, k := range []string{"forward_traffic", "gateway_selector", "ssh_session_recording", "rdp_session_recording"} {
+		if !d.NewValueKnown(k) {
+			return nil
+		}
 	}
 
-	status := resp.StatusCode()
+	forwardTraffic := d.Get("forward_traffic").(bool)
 
-	if status == 204 {
-		log.Printf("[INFO] Project %s was successfully updated", projectName)
-	} else {
-		return fmt.Errorf("[ERROR] Something went wrong while updating the project %s. Error: %s", projectName, resp)
+	for _, k := range []string{"ssh_session_recording", "rdp_session_recording"} {
+		if d.Get(k).(bool) && !forwardTraffic {
+			return fmt.Errorf("%s requires forward_traffic to be enabled", k)
+		}
+	}
+	if forwardTraffic && d.Get("gateway_selector").(string) == "" {
+		return fmt.Errorf("forward_traffic requires a gateway_selector")
+	}
 
+	return nil
+}
+
+// suppressAdvancedCounter hides the difference between a configured next UID
+// or GID and the one in ASA, which advances as users and groups are created
+// in the project. Only a value that has fallen below the configuration is
+// reported as drift.
+func suppressAdvancedCounter(k, old, new string, d *schema.ResourceData) bool {
+	current, err := strconv.Atoi(old)
+	if err != nil {
+		return false
+	}
+	configured, err := strconv.Atoi(new)
+	if err != nil {
+		return false
 	}
 
-	return resourceOKTAASAProjectRead(d, m)
+	return current >= configured
 }
 
-func resourceOKTAASAProjectDelete(d *schema.ResourceData, m interface{}) error {
-	token := m.(Bearer)
+func resourceOKTAASAProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
 
 	//get project_name from terraform config.
 	projectName := d.Get("project_name").(string)
 
-	resp, err := SendDelete(token.BearerToken, "/teams/"+teamName+"/projects/"+projectName, make([]byte, 0))
+	project := expandProject(d)
+
+	log.Printf("[DEBUG] Project PUT body: %+v", project)
+
+	//make API call to update project
+	err := client.UpdateProject(ctx, project)
 
 	if err != nil {
-		return fmt.Errorf("[ERROR] Error when deleting project: %s. Error: %s", projectName, err)
+		return errorDiag(err, "Error when updating project %s", projectName)
 	}
 
-	status := resp.StatusCode()
+	log.Printf("[INFO] Project %s was successfully updated", projectName)
+
+	return resourceOKTAASAProjectRead(ctx, d, m)
+}
+
+func resourceOKTAASAProjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	projectName := d.Id()
+
+	err := client.DeleteProject(ctx, projectName)
 
-	if status < 300 || status == 400 {
+	if err == nil || asa.IsNotFound(err) {
 		log.Printf("[INFO] Project %s was successfully deleted", projectName)
 	} else {
-		log.Printf("[ERROR] Something went wrong while deleting project %s. Error: %s", projectName, resp)
+		return errorDiag(err, "Error when deleting project %s", projectName)
 	}
 
 	return nil
@@ -1,22 +1,24 @@
 package oktaasa
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
 )
 
 func TestAccProject(t *testing.T) {
-	project := &Project{}
+	project := &asa.Project{}
 	projectName := "test-acc-project"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccProjectCheckDestroy(project),
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccProjectCheckDestroy(project),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccProjectCreateConfig,
@@ -31,6 +33,15 @@ func TestAccProject(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"oktaasa_project.test", "next_unix_gid", "63020",
 					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "create_server_users", "true",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "require_preauthorization", "false",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "ssh_certificate_type", "CERT_TYPE_ED25519_01",
+					),
 				),
 			},
 			{
@@ -46,13 +57,166 @@ func TestAccProject(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"oktaasa_project.test", "next_unix_gid", "63400",
 					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "create_server_users", "true",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "require_preauthorization", "true",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "ssh_certificate_type", "CERT_TYPE_RSA_01",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "user_on_demand_period", "8",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "force_shared_ssh_users", "true",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "shared_admin_user_name", "admin",
+					),
+				),
+			},
+			{
+				ResourceName:      "oktaasa_project.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// ASA advancing the counters past the configured values is not drift
+				PreConfig: testAccProjectSetCounters(t, projectName, 61250, 63450),
+				Config:    testAccProjectUpdateConfig,
+				PlanOnly:  true,
+			},
+			{
+				// counters below the configured values are
+				PreConfig:          testAccProjectSetCounters(t, projectName, 61100, 63450),
+				Config:             testAccProjectUpdateConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// ASA cannot rename projects, so a new one replaces the old one
+				Config: testAccProjectRenameConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccProjectCheckExists("oktaasa_project.test", project),
+					testAccProjectCheckDestroy(&asa.Project{Name: projectName}),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "project_name", projectName+"-renamed",
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_project.test", "next_unix_uid", "61200",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProject_createConflict(t *testing.T) {
+	projectName := "test-acc-project-conflict"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the project is created outside of Terraform first
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*asa.Client)
+					if err := client.CreateProject(context.Background(), asa.Project{Name: projectName, CreateServerUsers: true}); err != nil {
+						t.Fatalf("creating project %s: %s", projectName, err)
+					}
+					t.Cleanup(func() {
+						client.DeleteProject(context.Background(), projectName)
+					})
+				},
+				Config:      testAccProjectConflictConfig,
+				ExpectError: regexp.MustCompile("Error when creating project " + projectName),
+			},
+		},
+	})
+}
+
+func TestAccProject_gateways(t *testing.T) {
+	rn := "oktaasa_project.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccProjectCheckDestroy(&asa.Project{Name: "test-acc-project-gateways"}),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProjectGatewayConfig(false, "", true),
+				ExpectError: regexp.MustCompile("ssh_session_recording requires forward_traffic to be enabled"),
+			},
+			{
+				Config:      testAccProjectGatewayConfig(true, "", false),
+				ExpectError: regexp.MustCompile("forward_traffic requires a gateway_selector"),
+			},
+			{
+				Config:      testAccProjectGatewayConfig(true, "env=prod,region", true),
+				ExpectError: regexp.MustCompile("invalid label"),
+			},
+			{
+				Config: testAccProjectGatewayConfig(true, "env=prod,region=us-east-1", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rn, "forward_traffic", "true"),
+					resource.TestCheckResourceAttr(rn, "gateway_selector", "env=prod,region=us-east-1"),
+					resource.TestCheckResourceAttr(rn, "ssh_session_recording", "true"),
+					resource.TestCheckResourceAttr(rn, "rdp_session_recording", "true"),
 				),
 			},
 		},
 	})
 }
 
-func testAccProjectCheckExists(rn string, p *Project) resource.TestCheckFunc {
+func TestValidateGatewaySelector(t *testing.T) {
+	cases := map[string]bool{
+		"env=prod":                  true,
+		"env=prod,region=us-east-1": true,
+		"env=prod, tier=web":        true,
+		"app.kubernetes.io=gateway": true,
+		"":                          true,
+		"env":                       false,
+		"env=":                      false,
+		"=prod":                     false,
+		"env=prod,":                 false,
+		"env=prod=1":                false,
+		"env=pr od":                 false,
+	}
+
+	for selector, valid := range cases {
+		_, errs := validateGatewaySelector(selector, "gateway_selector")
+		if valid && len(errs) > 0 {
+			t.Errorf("%q: unexpected errors: %v", selector, errs)
+		}
+		if !valid && len(errs) == 0 {
+			t.Errorf("%q: expected an error", selector)
+		}
+	}
+}
+
+// testAccProjectSetCounters changes the next UID and GID of a project behind
+// Terraform's back.
+func testAccProjectSetCounters(t *testing.T, projectName string, uid, gid int) func() {
+	return func() {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		project, err := client.GetProject(context.Background(), projectName)
+		if err != nil {
+			t.Fatalf("reading project %s: %s", projectName, err)
+		}
+
+		project.NextUnixUID = uid
+		project.NextUnixGID = gid
+		if err := client.UpdateProject(context.Background(), *project); err != nil {
+			t.Fatalf("updating project %s: %s", projectName, err)
+		}
+	}
+}
+
+func testAccProjectCheckExists(rn string, p *asa.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
 		if !ok {
@@ -64,38 +228,31 @@ func testAccProjectCheckExists(rn string, p *Project) resource.TestCheckFunc {
 			return fmt.Errorf("resource id not set")
 		}
 
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+rs.Primary.ID)
+		found, err := client.GetProject(context.Background(), rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		err = json.Unmarshal(resp.Body(), p)
-		if err != nil {
-			return fmt.Errorf("error unmarshaling data source response: %s", err)
-		}
+		*p = *found
 
 		return nil
 	}
 }
 
-func testAccProjectCheckDestroy(p *Project) resource.TestCheckFunc {
+func testAccProjectCheckDestroy(p *asa.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		config := testAccProvider.Meta().(Bearer)
+		client := testAccProvider.Meta().(*asa.Client)
 
-		resp, err := SendGet(config.BearerToken, "/teams/"+config.TeamName+"/projects/"+p.Name)
-		if err != nil {
+		found, err := client.GetProject(context.Background(), p.Name)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
 		}
 
-		status := resp.StatusCode()
-		deleted, err := checkSoftDelete(resp.Body())
-		if err != nil {
-			return fmt.Errorf("error while checking deleted status: %s", err)
-		}
-
-		if status == 200 && !deleted {
+		if !found.Deleted() {
 			return fmt.Errorf("project still exists")
 		}
 
@@ -115,4 +272,32 @@ resource "oktaasa_project" "test" {
     project_name = "test-acc-project"
   	next_unix_uid = 61200
   	next_unix_gid = 63400
+  	require_preauthorization = true
+  	ssh_certificate_type = "CERT_TYPE_RSA_01"
+  	user_on_demand_period = 8
+  	force_shared_ssh_users = true
+  	shared_admin_user_name = "admin"
 }`
+
+const testAccProjectRenameConfig = `
+resource "oktaasa_project" "test" {
+    project_name = "test-acc-project-renamed"
+  	next_unix_uid = 61200
+  	next_unix_gid = 63400
+}`
+
+const testAccProjectConflictConfig = `
+resource "oktaasa_project" "test" {
+    project_name = "test-acc-project-conflict"
+}`
+
+func testAccProjectGatewayConfig(forwardTraffic bool, gatewaySelector string, recording bool) string {
+	return fmt.Sprintf(`
+resource "oktaasa_project" "test" {
+    project_name = "test-acc-project-gateways"
+  	forward_traffic = %t
+  	gateway_selector = %q
+  	ssh_session_recording = %t
+  	rdp_session_recording = %t
+}`, forwardTraffic, gatewaySelector, recording, recording)
+}
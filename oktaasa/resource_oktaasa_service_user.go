@@ -0,0 +1,137 @@
+package oktaasa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func resourceOKTAASAServiceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOKTAASAServiceUserCreate,
+		ReadContext:   resourceOKTAASAServiceUserRead,
+		UpdateContext: resourceOKTAASAServiceUserUpdate,
+		DeleteContext: resourceOKTAASAServiceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  asa.UserStatusActive,
+				ValidateFunc: validation.StringInSlice([]string{
+					asa.UserStatusActive,
+					asa.UserStatusDisabled,
+				}, false),
+			},
+			// Computed
+			"user_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceOKTAASAServiceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	//get settings from terraform config.
+	userName := d.Get("name").(string)
+	status := d.Get("status").(string)
+
+	log.Printf("[DEBUG] Creating service user %s", userName)
+
+	_, err := client.CreateServiceUser(ctx, asa.ServiceUser{Name: userName})
+
+	if asa.IsConflict(err) {
+		// ASA cannot delete service users, so this may be one disabled on
+		// purpose, or by an earlier destroy. Either way, it is not taken
+		// over without an import.
+		return errorDiag(err, "Service user %s already exists, import it to manage it with Terraform", userName)
+	} else if err != nil {
+		return errorDiag(err, "Error when creating service user %s", userName)
+	}
+
+	d.SetId(userName)
+
+	// new service users are active.
+	if status != asa.UserStatusActive {
+		if err := client.UpdateServiceUser(ctx, asa.ServiceUser{Name: userName, Status: status}); err != nil {
+			return errorDiag(err, "Error when setting status of service user %s to %s", userName, status)
+		}
+	}
+
+	log.Printf("[DEBUG] Success. Service user %s was created", userName)
+
+	return resourceOKTAASAServiceUserRead(ctx, d, m)
+}
+
+func resourceOKTAASAServiceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	userName := d.Id()
+
+	user, err := client.GetServiceUser(ctx, userName)
+
+	if asa.IsNotFound(err) {
+		log.Printf("[INFO] Service user %s does not exist", userName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading service user %s", userName)
+	}
+
+	if user.Status == asa.UserStatusDeleted {
+		log.Printf("[INFO] Service user %s was deleted", userName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", user.Name)
+	d.Set("status", user.Status)
+	d.Set("user_id", user.ID)
+
+	return nil
+}
+
+func resourceOKTAASAServiceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	userName := d.Id()
+	status := d.Get("status").(string)
+
+	log.Printf("[DEBUG] Setting status of service user %s to %s", userName, status)
+
+	if err := client.UpdateServiceUser(ctx, asa.ServiceUser{Name: userName, Status: status}); err != nil {
+		return errorDiag(err, "Error when setting status of service user %s to %s", userName, status)
+	}
+
+	return resourceOKTAASAServiceUserRead(ctx, d, m)
+}
+
+// resourceOKTAASAServiceUserDelete disables the service user, as ASA does not
+// delete them.
+func resourceOKTAASAServiceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	userName := d.Id()
+
+	err := client.UpdateServiceUser(ctx, asa.ServiceUser{Name: userName, Status: asa.UserStatusDisabled})
+
+	if err == nil || asa.IsNotFound(err) {
+		log.Printf("[INFO] Service user %s was successfully disabled", userName)
+	} else {
+		return errorDiag(err, "Error when disabling service user %s", userName)
+	}
+
+	return nil
+}
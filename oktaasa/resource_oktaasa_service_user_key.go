@@ -0,0 +1,171 @@
+package oktaasa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func resourceOKTAASAServiceUserKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOKTAASAServiceUserKeyCreate,
+		ReadContext:   resourceOKTAASAServiceUserKeyRead,
+		UpdateContext: resourceOKTAASAServiceUserKeyUpdate,
+		DeleteContext: resourceOKTAASAServiceUserKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOKTAASAServiceUserKeyImport,
+		},
+		CustomizeDiff: rotateAfterCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"user_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rotate_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+			},
+			"pgp_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validatePGPKey,
+			},
+			// Computed
+			"key_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"secret": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"encrypted_secret": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issued_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceOKTAASAServiceUserKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	//get user_name from terraform config.
+	userName := d.Get("user_name").(string)
+
+	log.Printf("[DEBUG] Issuing API key for service user %s", userName)
+
+	key, err := client.CreateServiceUserKey(ctx, userName)
+
+	if err != nil {
+		return errorDiag(err, "Error when issuing API key for service user %s", userName)
+	}
+
+	// the secret is only returned now; Read cannot restore it. It is
+	// encrypted before the ID is set, and the key is revoked if that fails,
+	// so no key is left that Terraform does not know about.
+	if pgpKey, ok := d.GetOk("pgp_key"); ok {
+		fingerprint, encrypted, err := encryptValue(pgpKey.(string), key.Secret)
+		if err != nil {
+			if err := client.DeleteServiceUserKey(ctx, userName, key.ID); err != nil {
+				log.Printf("[WARN] Could not revoke API key %s of service user %s: %s", key.ID, userName, err)
+			}
+			return errorDiag(err, "Error when encrypting API key %s of service user %s", key.ID, userName)
+		}
+		d.Set("key_fingerprint", fingerprint)
+		d.Set("encrypted_secret", encrypted)
+	} else {
+		d.Set("secret", key.Secret)
+	}
+
+	d.SetId(userName + "/" + key.ID)
+
+	log.Printf("[DEBUG] Success. API key %s was issued for service user %s", key.ID, userName)
+
+	return resourceOKTAASAServiceUserKeyRead(ctx, d, m)
+}
+
+func resourceOKTAASAServiceUserKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	parts, err := splitImportID(d.Id(), "user/key_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userName, keyID := parts[0], parts[1]
+
+	key, err := client.FindServiceUserKey(ctx, userName, keyID)
+
+	if asa.IsNotFound(err) || err == nil && key == nil {
+		log.Printf("[INFO] API key %s of service user %s does not exist", keyID, userName)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return errorDiag(err, "Error when reading API key %s of service user %s", keyID, userName)
+	}
+
+	d.Set("user_name", userName)
+	d.Set("key_id", key.ID)
+	d.Set("issued_at", key.IssuedAt)
+
+	return nil
+}
+
+func resourceOKTAASAServiceUserKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// every attribute but rotate_after, which is not sent to the API, forces
+	// a new key.
+	return resourceOKTAASAServiceUserKeyRead(ctx, d, m)
+}
+
+func resourceOKTAASAServiceUserKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	parts, err := splitImportID(d.Id(), "user/key_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userName, keyID := parts[0], parts[1]
+
+	err = client.DeleteServiceUserKey(ctx, userName, keyID)
+
+	if err == nil || asa.IsNotFound(err) {
+		log.Printf("[INFO] API key %s of service user %s was successfully revoked", keyID, userName)
+	} else {
+		return errorDiag(err, "Error when revoking API key %s of service user %s", keyID, userName)
+	}
+
+	return nil
+}
+
+// resourceOKTAASAServiceUserKeyImport imports an API key from an ID of the
+// form user/key_id, which is also the resource ID. The secret cannot be
+// imported.
+func resourceOKTAASAServiceUserKeyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, err := splitImportID(d.Id(), "user/key_id"); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,147 @@
+package oktaasa
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func TestAccServiceUserKey(t *testing.T) {
+	var keyID string
+	_, pgpKey := testPGPKey(t)
+	userName := "test-acc-service-user-key"
+	rn := "oktaasa_service_user_key.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccServiceUserKeyCheckDestroy(userName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceUserKeyConfig(userName, "1", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccServiceUserKeyCheckExists(rn, &keyID),
+					resource.TestCheckResourceAttr(rn, "user_name", userName),
+					resource.TestCheckResourceAttrSet(rn, "secret"),
+					resource.TestCheckResourceAttrSet(rn, "issued_at"),
+					resource.TestCheckNoResourceAttr(rn, "encrypted_secret"),
+				),
+			},
+			{
+				ResourceName:            rn,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"secret", "keepers", "rotate_after"},
+			},
+			{
+				// a new key replaces the old one, which is revoked
+				Config: testAccServiceUserKeyConfig(userName, "2", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccServiceUserKeyCheckRevoked(userName, &keyID),
+					testAccServiceUserKeyCheckExists(rn, &keyID),
+				),
+			},
+			{
+				// a key that cannot encrypt is rejected before a key is issued
+				Config:      testAccServiceUserKeyConfig(userName, "2", testPGPSignOnlyKey(t)),
+				ExpectError: regexp.MustCompile("no valid encryption key"),
+			},
+			{
+				Config: testAccServiceUserKeyConfig(userName, "2", pgpKey),
+				Check: resource.ComposeTestCheckFunc(
+					testAccServiceUserKeyCheckRevoked(userName, &keyID),
+					testAccServiceUserKeyCheckExists(rn, &keyID),
+					resource.TestCheckNoResourceAttr(rn, "secret"),
+					resource.TestCheckResourceAttrSet(rn, "encrypted_secret"),
+					resource.TestCheckResourceAttrSet(rn, "key_fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+// testAccServiceUserKeyCheckExists checks that the key in state exists in ASA
+// and records its ID.
+func testAccServiceUserKeyCheckExists(rn string, keyID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		// resource ID is user/key_id
+		if !strings.HasSuffix(rs.Primary.ID, "/"+rs.Primary.Attributes["key_id"]) {
+			return fmt.Errorf("unexpected resource id %s", rs.Primary.ID)
+		}
+
+		client := testAccProvider.Meta().(*asa.Client)
+
+		found, err := client.FindServiceUserKey(context.Background(), rs.Primary.Attributes["user_name"], rs.Primary.Attributes["key_id"])
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+		if found == nil {
+			return fmt.Errorf("key %s does not exist", rs.Primary.Attributes["key_id"])
+		}
+
+		*keyID = found.ID
+
+		return nil
+	}
+}
+
+// testAccServiceUserKeyCheckRevoked checks that the key recorded by the last
+// testAccServiceUserKeyCheckExists was revoked.
+func testAccServiceUserKeyCheckRevoked(userName string, keyID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		found, err := client.FindServiceUserKey(context.Background(), userName, *keyID)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+		if found != nil {
+			return fmt.Errorf("key %s was not revoked", *keyID)
+		}
+
+		return nil
+	}
+}
+
+func testAccServiceUserKeyCheckDestroy(userName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		keys := client.ServiceUserKeys(userName)
+		if keys.Next(context.Background()) {
+			return fmt.Errorf("service user %s still has API keys", userName)
+		}
+		if err := keys.Err(); err != nil && !asa.IsNotFound(err) {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		return nil
+	}
+}
+
+func testAccServiceUserKeyConfig(userName, revision, pgpKey string) string {
+	return fmt.Sprintf(`
+resource "oktaasa_service_user" "test" {
+    name = %q
+}
+
+resource "oktaasa_service_user_key" "test" {
+    user_name = oktaasa_service_user.test.name
+    pgp_key = %q != "" ? %q : null
+
+    keepers = {
+      revision = %q
+    }
+}`, userName, pgpKey, pgpKey, revision)
+}
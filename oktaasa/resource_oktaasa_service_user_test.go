@@ -0,0 +1,135 @@
+package oktaasa
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func TestAccServiceUser(t *testing.T) {
+	user := &asa.ServiceUser{}
+	userName := "test-acc-service-user"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccServiceUserCheckDestroy(userName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceUserConfig(userName, "ACTIVE"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccServiceUserCheckExists("oktaasa_service_user.test", user),
+					resource.TestCheckResourceAttr(
+						"oktaasa_service_user.test", "name", userName,
+					),
+					resource.TestCheckResourceAttr(
+						"oktaasa_service_user.test", "status", "ACTIVE",
+					),
+					resource.TestCheckResourceAttrSet(
+						"oktaasa_service_user.test", "user_id",
+					),
+				),
+			},
+			{
+				Config: testAccServiceUserConfig(userName, "DISABLED"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccServiceUserCheckExists("oktaasa_service_user.test", user),
+					resource.TestCheckResourceAttr(
+						"oktaasa_service_user.test", "status", "DISABLED",
+					),
+				),
+			},
+			{
+				ResourceName:      "oktaasa_service_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccServiceUser_existing(t *testing.T) {
+	userName := "test-acc-service-user-existing"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccServiceUserCheckDestroy(userName),
+		Steps: []resource.TestStep{
+			{
+				// a disabled service user Terraform does not manage
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*asa.Client)
+					if _, err := client.CreateServiceUser(context.Background(), asa.ServiceUser{Name: userName}); err != nil {
+						t.Fatalf("creating service user %s: %s", userName, err)
+					}
+					if err := client.UpdateServiceUser(context.Background(), asa.ServiceUser{Name: userName, Status: asa.UserStatusDisabled}); err != nil {
+						t.Fatalf("disabling service user %s: %s", userName, err)
+					}
+				},
+				// it stays disabled, as the destroy check verifies
+				Config:      testAccServiceUserConfig(userName, "ACTIVE"),
+				ExpectError: regexp.MustCompile("already exists, import it"),
+			},
+		},
+	})
+}
+
+func testAccServiceUserCheckExists(rn string, u *asa.ServiceUser) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		// resource ID is the service user name
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("resource id not set")
+		}
+
+		client := testAccProvider.Meta().(*asa.Client)
+
+		found, err := client.GetServiceUser(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		*u = *found
+
+		return nil
+	}
+}
+
+// testAccServiceUserCheckDestroy checks that the service user was disabled,
+// as ASA does not delete service users.
+func testAccServiceUserCheckDestroy(userName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		found, err := client.GetServiceUser(context.Background(), userName)
+		if asa.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		if found.Status == asa.UserStatusActive {
+			return fmt.Errorf("service user is still active")
+		}
+
+		return nil
+	}
+}
+
+func testAccServiceUserConfig(name, status string) string {
+	return fmt.Sprintf(`
+resource "oktaasa_service_user" "test" {
+    name = %q
+    status = %q
+}`, name, status)
+}
@@ -0,0 +1,131 @@
+package oktaasa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+// userAttributeNames are the user attributes that can be managed by
+// oktaasa_user_attributes, which are also its schema keys.
+var userAttributeNames = []string{
+	asa.AttributeUnixUserName,
+	asa.AttributeUnixUID,
+	asa.AttributeWindowsUserName,
+}
+
+func resourceOKTAASAUserAttributes() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceOKTAASAUserAttributesCreate,
+		ReadContext:   resourceOKTAASAUserAttributesRead,
+		UpdateContext: resourceOKTAASAUserAttributesUpdate,
+		DeleteContext: resourceOKTAASAUserAttributesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceOKTAASAUserAttributesImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			asa.AttributeUnixUserName: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			asa.AttributeUnixUID: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(100, 2147483647),
+			},
+			asa.AttributeWindowsUserName: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceOKTAASAUserAttributesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+
+	//get user_name from terraform config.
+	userName := d.Get("user_name").(string)
+
+	log.Printf("[DEBUG] Setting attributes of user %s", userName)
+
+	if diags := userAttributeOwner(client, userName).apply(ctx, d, userAttributeNames); diags != nil {
+		return diags
+	}
+
+	d.SetId(userName)
+
+	log.Printf("[DEBUG] Success. Attributes of user %s were set", userName)
+
+	return resourceOKTAASAUserAttributesRead(ctx, d, m)
+}
+
+func resourceOKTAASAUserAttributesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	userName := d.Id()
+
+	found, diags := userAttributeOwner(client, userName).read(ctx, d, managedAttributes(d, userAttributeNames))
+	if diags != nil {
+		return diags
+	}
+
+	if !found {
+		log.Printf("[INFO] User %s does not exist", userName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("user_name", userName)
+
+	return nil
+}
+
+func resourceOKTAASAUserAttributesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	userName := d.Id()
+
+	log.Printf("[DEBUG] Updating attributes of user %s", userName)
+
+	if diags := userAttributeOwner(client, userName).apply(ctx, d, userAttributeNames); diags != nil {
+		return diags
+	}
+
+	return resourceOKTAASAUserAttributesRead(ctx, d, m)
+}
+
+// resourceOKTAASAUserAttributesDelete removes the managed attributes, so ASA
+// goes back to deriving them from the user's profile.
+func resourceOKTAASAUserAttributesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*asa.Client)
+	userName := d.Id()
+
+	if diags := userAttributeOwner(client, userName).removeAll(ctx, managedAttributes(d, userAttributeNames)); diags != nil {
+		return diags
+	}
+
+	log.Printf("[INFO] Attributes of user %s were successfully removed", userName)
+
+	return nil
+}
+
+// resourceOKTAASAUserAttributesImport imports the attributes the user has,
+// which the imported resource then manages.
+func resourceOKTAASAUserAttributesImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*asa.Client)
+
+	if err := userAttributeOwner(client, d.Id()).importAll(ctx, d, userAttributeNames); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,184 @@
+package oktaasa
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/terraform-providers/terraform-provider-oktaasa/asa"
+)
+
+func TestAccUserAttributes(t *testing.T) {
+	userName := "test-acc-user-attributes"
+	otherName := "test-acc-user-attributes-other"
+	rn := "oktaasa_user_attributes.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserAttributesCheckDestroy(userName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserAttributesConfig(userName, otherName, "legacy", 60001, 60002),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAttributesCheckExists(rn, asa.AttributeUnixUID, "60001"),
+					resource.TestCheckResourceAttr(rn, "user_name", userName),
+					resource.TestCheckResourceAttr(rn, "unix_user_name", "legacy"),
+					resource.TestCheckResourceAttr(rn, "unix_uid", "60001"),
+					resource.TestCheckNoResourceAttr(rn, "windows_user_name"),
+				),
+			},
+			{
+				Config: testAccUserAttributesConfig(userName, otherName, "legacy2", 60003, 60002),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAttributesCheckExists(rn, asa.AttributeUnixUserName, "legacy2"),
+					testAccUserAttributesCheckExists(rn, asa.AttributeUnixUID, "60003"),
+					resource.TestCheckResourceAttr(rn, "unix_uid", "60003"),
+				),
+			},
+			{
+				ResourceName:      rn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// an attribute set outside of Terraform is left alone, and
+				// one dropped from the configuration is removed
+				PreConfig: func() {
+					testAccUserAttributesSet(t, userName, asa.AttributeWindowsUserName, "legacy-win")
+				},
+				Config: testAccUserAttributesConfig(userName, otherName, "", 60003, 60002),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAttributesCheckExists(rn, asa.AttributeWindowsUserName, "legacy-win"),
+					testAccUserAttributesCheckAbsent(userName, asa.AttributeUnixUserName),
+					resource.TestCheckResourceAttr(rn, "unix_user_name", ""),
+					resource.TestCheckNoResourceAttr(rn, "windows_user_name"),
+				),
+			},
+			{
+				// the UID is taken by the other user
+				Config:      testAccUserAttributesConfig(userName, otherName, "legacy2", 60002, 60002),
+				ExpectError: regexp.MustCompile("60002 is already used by another user"),
+			},
+		},
+	})
+}
+
+// testAccUserAttributesCheckExists checks that the user in state has the
+// attribute name set to value in ASA.
+func testAccUserAttributesCheckExists(rn, name, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		// resource ID is the user name
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("resource id not set")
+		}
+
+		client := testAccProvider.Meta().(*asa.Client)
+
+		attributes, err := client.UserAttributes(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		for _, a := range attributes {
+			if a.Name == name {
+				if a.String() != value {
+					return fmt.Errorf("%s of user %s is %s, expected %s", name, rs.Primary.ID, a.String(), value)
+				}
+				return nil
+			}
+		}
+
+		return fmt.Errorf("user %s has no %s", rs.Primary.ID, name)
+	}
+}
+
+// testAccUserAttributesSet sets an attribute of a user, as if it was done in
+// the ASA console.
+func testAccUserAttributesSet(t *testing.T, userName, name string, value interface{}) {
+	client := testAccProvider.Meta().(*asa.Client)
+
+	if err := client.CreateUserAttribute(context.Background(), userName, asa.Attribute{Name: name, Value: value}); err != nil {
+		t.Fatalf("setting %s of user %s: %s", name, userName, err)
+	}
+}
+
+// testAccUserAttributesCheckAbsent checks that the user has none of the given
+// attributes in ASA.
+func testAccUserAttributesCheckAbsent(userName string, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*asa.Client)
+
+		attributes, err := client.UserAttributes(context.Background(), userName)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		for _, a := range attributes {
+			for _, name := range names {
+				if a.Name == name {
+					return fmt.Errorf("user %s still has %s", userName, name)
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccUserAttributesCheckDestroy checks that the managed attributes were
+// removed and the one set in the console was kept, then removes that too.
+func testAccUserAttributesCheckDestroy(userName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccUserAttributesCheckAbsent(userName, asa.AttributeUnixUserName, asa.AttributeUnixUID)(s); err != nil {
+			return err
+		}
+
+		client := testAccProvider.Meta().(*asa.Client)
+
+		attributes, err := client.UserAttributes(context.Background(), userName)
+		if err != nil {
+			return fmt.Errorf("error getting data source: %s", err)
+		}
+
+		for _, a := range attributes {
+			if a.Name == asa.AttributeWindowsUserName {
+				return client.DeleteUserAttribute(context.Background(), userName, a.ID)
+			}
+		}
+
+		return fmt.Errorf("user %s lost %s, which Terraform does not manage", userName, asa.AttributeWindowsUserName)
+	}
+}
+
+func testAccUserAttributesConfig(userName, otherName, unixUserName string, uid, otherUID int) string {
+	return fmt.Sprintf(`
+resource "oktaasa_service_user" "test" {
+    name = %q
+}
+
+resource "oktaasa_service_user" "other" {
+    name = %q
+}
+
+resource "oktaasa_user_attributes" "other" {
+    user_name = oktaasa_service_user.other.name
+    unix_uid = %d
+}
+
+resource "oktaasa_user_attributes" "test" {
+    user_name = oktaasa_service_user.test.name
+    unix_user_name = %q != "" ? %q : null
+    unix_uid = %d
+
+    depends_on = [oktaasa_user_attributes.other]
+}`, userName, otherName, otherUID, unixUserName, unixUserName, uid)
+}